@@ -3,6 +3,7 @@ package builder
 // Command builder for a `docker build` command.
 //
 // Supported segments:
+// - `host`: What docker daemon the command should run against
 // - `path`: Path to the Dockerfile.
 // - `file`: What file should be used as Dockerfile.
 // - `tag`: Tag to be used for the image.
@@ -56,18 +57,39 @@ func (c *DockerBuildCommandBuilder) BuildArg(arg string) *DockerBuildCommandBuil
 	return c
 }
 
+// `--host` The docker daemon to run the command against.
+// Nothing is added for an empty host, so the default one is used.
+func (c *DockerBuildCommandBuilder) Host(host string) *DockerBuildCommandBuilder {
+	if host == "" {
+		return c
+	}
+
+	c.Segments = append(c.Segments, Segment{
+		Priority: -10,
+		Key:      "--host",
+		Value:    host,
+	})
+
+	return c
+}
+
 // Creates a new instance of a Docker Build Builder which can
 // be used to dynamically build a docker build command.
 func DockerBuildCommand() *DockerBuildCommandBuilder {
 	return &DockerBuildCommandBuilder{
-		Command: "docker build",
+		Command: "docker",
 		Segments: []Segment{
-			{},
+			{Key: "build"},
 		},
 	}
 }
 
+// Interface implementation
+func (c *DockerBuildCommandBuilder) Args() []string {
+	return BuildArgs(c.Command, c.Segments...)
+}
+
 // Interface implementation
 func (c *DockerBuildCommandBuilder) Build() string {
-	return BuildCommand(c.Command, c.Segments...)
+	return Quote(c.Args())
 }
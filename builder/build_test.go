@@ -1,6 +1,9 @@
 package builder
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestBuildCommand(t *testing.T) {
 	cmd := DockerBuildCommand().
@@ -16,3 +19,43 @@ func TestBuildCommand(t *testing.T) {
 		t.Errorf("Expected %s, got %s", expected, cmd)
 	}
 }
+
+func TestBuildCommandWithHost(t *testing.T) {
+	cmd := DockerBuildCommand().
+		Host("tcp://10.0.0.2:2375").
+		Tag("thing:latest").
+		Path(".").
+		Build()
+
+	expected := "docker --host tcp://10.0.0.2:2375 build -t thing:latest ."
+
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
+
+func TestBuildCommandKeepsPathsWithSpacesTogether(t *testing.T) {
+	command := DockerBuildCommand().
+		Tag("thing:latest").
+		File("/home/me/My Projects/api/Dockerfile").
+		Path("/home/me/My Projects/api")
+
+	args := command.Args()
+	expected := []string{
+		"docker", "build",
+		"-f", "/home/me/My Projects/api/Dockerfile",
+		"-t", "thing:latest",
+		"/home/me/My Projects/api",
+	}
+
+	if strings.Join(args, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, args)
+	}
+
+	built := command.Build()
+	quoted := "docker build -f '/home/me/My Projects/api/Dockerfile' -t thing:latest '/home/me/My Projects/api'"
+
+	if built != quoted {
+		t.Errorf("Expected %s, got %s", quoted, built)
+	}
+}
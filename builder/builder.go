@@ -1,48 +1,69 @@
 package builder
 
-import "sort"
+import (
+	"sort"
+
+	"github.com/kballard/go-shellquote"
+)
 
 // A Segment is the building block that all the builders
 // use to compose different commands.
 //
 // They all define their own segments and keep track of
-// the correct prioritites.
+// the correct prioritites. The key, the value, and each of the
+// args always end up as separate arguments, no matter what's in them.
 type Segment struct {
 	Priority int
 	Key      string
 	Value    string
+	Args     []string // Any arguments that follow the value
 }
 
 // Something that all the builders should implement,
 // so that there's a generalized way that they all build
 // compose themselves.
+//
+// The arguments are what actually gets run, while the built
+// string is only ever meant for people to read, or to paste
+// into a shell.
 type Command interface {
+	Args() []string
 	Build() string
 }
 
 // A default way of sorting all the segments based on their
-// priority and making sure they get inserted properly.
+// priority and turning them into the arguments of the command.
 //
-// If the segment has a key, the value should be appended after the
-// key and separated by a space.
+// Segments with the same priority keep the order they were
+// added in.
 //
-// If the segment has no key, the value should be appended to the
-// command normally with just a space.
-func BuildCommand(command string, segments ...Segment) string {
+// If the segment has a key, the value should come right after it
+// as a separate argument. Empty keys and values are left out.
+func BuildArgs(command string, segments ...Segment) []string {
 	// Sort segments based on priority
-	sort.Slice(segments, func(i, j int) bool {
+	sort.SliceStable(segments, func(i, j int) bool {
 		return segments[i].Priority < segments[j].Priority
 	})
 
+	args := []string{command}
+
 	for _, segment := range segments {
 		if segment.Key != "" {
-			command += " " + segment.Key
+			args = append(args, segment.Key)
 		}
 
 		if segment.Value != "" {
-			command += " " + segment.Value
+			args = append(args, segment.Value)
 		}
+
+		args = append(args, segment.Args...)
 	}
 
-	return command
+	return args
+}
+
+// Joins the given arguments into a single string that a
+// shell would split back into the exact same arguments.
+func Quote(args []string) string {
+	return shellquote.Join(args...)
 }
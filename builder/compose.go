@@ -1,8 +1,11 @@
 package builder
 
+import "strconv"
+
 // Command builder for a `docker compose` command.
 //
 // Supported segments:
+// - `host`: What docker daemon the command should run against
 // - `file`: What file should be used as the docker-compose.yml
 // - `env-file`: What file(s) should be used as the .env file(s)
 // - `background`: Should the command be run in the background
@@ -11,6 +14,19 @@ package builder
 // - `down`: Take down the entire compose file
 // - `stop`: Stop the services
 // - `restart`: Restart the services
+// - `ps`: List the containers of the services
+// - `pull`: Pull the images of the services
+// - `build`: Build the images of the services
+// - `config`: Validate and show the compose file
+// - `exec`: Run a command in a running service
+// - `run`: Run a one-off command in a new container of a service
+// - `logs`: Show the output of the services
+// - `rm`: Remove the stopped containers of the services
+// - `invoke`: The command `exec` and `run` should run
+// - `project-name`: What the compose project should be called
+// - `profile`: What profile(s) should be enabled
+// - `remove-orphans`, `force-recreate`, `no-deps`, `timeout`, `auto-remove`:
+// The flags of the subcommands that support them
 type DockerComposeCommandBuilder struct {
 	Command  string
 	Segments []Segment
@@ -50,19 +66,18 @@ func (c *DockerComposeCommandBuilder) Background() *DockerComposeCommandBuilder
 	return c
 }
 
-// A list of space separated service names that should run
-func (c *DockerComposeCommandBuilder) Service(service string) *DockerComposeCommandBuilder {
+// One or more service names that should run
+func (c *DockerComposeCommandBuilder) Service(services ...string) *DockerComposeCommandBuilder {
 	c.Segments = append(c.Segments, Segment{
 		Priority: 1001,
-		Key:      service,
-		Value:    "",
+		Args:     services,
 	})
 
 	return c
 }
 
 // `up` for starting the service
-// This is unique and competes with `down`, `stop`, and `restart`
+// This is unique and competes with all the other subcommands
 func (c *DockerComposeCommandBuilder) Up() *DockerComposeCommandBuilder {
 	segment := Segment{
 		Priority: 999,
@@ -76,7 +91,7 @@ func (c *DockerComposeCommandBuilder) Up() *DockerComposeCommandBuilder {
 }
 
 // `down` for taking down the entire compose file
-// This is unique and competes with `up`, `stop`, and `restart`
+// This is unique and competes with all the other subcommands
 func (c *DockerComposeCommandBuilder) Down() *DockerComposeCommandBuilder {
 	segment := Segment{
 		Priority: 999,
@@ -90,7 +105,7 @@ func (c *DockerComposeCommandBuilder) Down() *DockerComposeCommandBuilder {
 }
 
 // `stop` for stopping one or more services
-// This is unique and competes with `up`, `down`, and `restart`
+// This is unique and competes with all the other subcommands
 func (c *DockerComposeCommandBuilder) Stop() *DockerComposeCommandBuilder {
 	segment := Segment{
 		Priority: 999,
@@ -104,7 +119,7 @@ func (c *DockerComposeCommandBuilder) Stop() *DockerComposeCommandBuilder {
 }
 
 // `restart` for restarting one or more services
-// This is unique and competes with `up`, `down`, and `stop`
+// This is unique and competes with all the other subcommands
 func (c *DockerComposeCommandBuilder) Restart() *DockerComposeCommandBuilder {
 	segment := Segment{
 		Priority: 999,
@@ -117,20 +132,248 @@ func (c *DockerComposeCommandBuilder) Restart() *DockerComposeCommandBuilder {
 	return c
 }
 
+// `ps` for listing the containers of one or more services
+// This is unique and competes with all the other subcommands
+func (c *DockerComposeCommandBuilder) Ps() *DockerComposeCommandBuilder {
+	segment := Segment{
+		Priority: 999,
+		Key:      "ps",
+		Value:    "",
+	}
+
+	c.Unique[segment.Priority] = segment
+
+	return c
+}
+
+// `pull` for pulling the images of one or more services
+// This is unique and competes with all the other subcommands
+func (c *DockerComposeCommandBuilder) Pull() *DockerComposeCommandBuilder {
+	segment := Segment{
+		Priority: 999,
+		Key:      "pull",
+		Value:    "",
+	}
+
+	c.Unique[segment.Priority] = segment
+
+	return c
+}
+
+// `build` for building the images of one or more services
+// Not called `Build` since that one builds the command itself
+// This is unique and competes with all the other subcommands
+func (c *DockerComposeCommandBuilder) BuildImages() *DockerComposeCommandBuilder {
+	segment := Segment{
+		Priority: 999,
+		Key:      "build",
+		Value:    "",
+	}
+
+	c.Unique[segment.Priority] = segment
+
+	return c
+}
+
+// `config` for validating and showing the entire compose file
+// This is unique and competes with all the other subcommands
+func (c *DockerComposeCommandBuilder) Config() *DockerComposeCommandBuilder {
+	segment := Segment{
+		Priority: 999,
+		Key:      "config",
+		Value:    "",
+	}
+
+	c.Unique[segment.Priority] = segment
+
+	return c
+}
+
+// `exec` for running a command within a running service
+// The command itself is given with `Invoke`
+// This is unique and competes with all the other subcommands
+func (c *DockerComposeCommandBuilder) Exec() *DockerComposeCommandBuilder {
+	segment := Segment{
+		Priority: 999,
+		Key:      "exec",
+		Value:    "",
+	}
+
+	c.Unique[segment.Priority] = segment
+
+	return c
+}
+
+// `run` for running a one-off command in a new container of a service
+// The command itself is given with `Invoke`
+// This is unique and competes with all the other subcommands
+func (c *DockerComposeCommandBuilder) Run() *DockerComposeCommandBuilder {
+	segment := Segment{
+		Priority: 999,
+		Key:      "run",
+		Value:    "",
+	}
+
+	c.Unique[segment.Priority] = segment
+
+	return c
+}
+
+// `logs` for showing the output of one or more services
+// This is unique and competes with all the other subcommands
+func (c *DockerComposeCommandBuilder) Logs() *DockerComposeCommandBuilder {
+	segment := Segment{
+		Priority: 999,
+		Key:      "logs",
+		Value:    "",
+	}
+
+	c.Unique[segment.Priority] = segment
+
+	return c
+}
+
+// `rm` for removing the stopped containers of one or more services
+// This is unique and competes with all the other subcommands
+func (c *DockerComposeCommandBuilder) Rm() *DockerComposeCommandBuilder {
+	segment := Segment{
+		Priority: 999,
+		Key:      "rm",
+		Value:    "",
+	}
+
+	c.Unique[segment.Priority] = segment
+
+	return c
+}
+
+// The command, along with its arguments, that `exec` or `run`
+// should run within the service. It always comes after the service.
+// This is unique, only the last one is ever run.
+func (c *DockerComposeCommandBuilder) Invoke(command ...string) *DockerComposeCommandBuilder {
+	segment := Segment{
+		Priority: 1002,
+		Args:     command,
+	}
+
+	c.Unique[segment.Priority] = segment
+
+	return c
+}
+
+// `--project-name` what the compose project should be called
+// instead of the name of the directory the file is in
+func (c *DockerComposeCommandBuilder) ProjectName(name string) *DockerComposeCommandBuilder {
+	c.Segments = append(c.Segments, Segment{
+		Priority: 10,
+		Key:      "--project-name",
+		Value:    name,
+	})
+
+	return c
+}
+
+// `--profile` what profile should be enabled, can be given
+// more than once to enable several of them
+func (c *DockerComposeCommandBuilder) Profile(profile string) *DockerComposeCommandBuilder {
+	c.Segments = append(c.Segments, Segment{
+		Priority: 10,
+		Key:      "--profile",
+		Value:    profile,
+	})
+
+	return c
+}
+
+// `--remove-orphans` removes the containers of services that
+// aren't in the compose file anymore, for `up` and `down`
+func (c *DockerComposeCommandBuilder) RemoveOrphans() *DockerComposeCommandBuilder {
+	c.Segments = append(c.Segments, Segment{
+		Priority: 1000,
+		Key:      "--remove-orphans",
+		Value:    "",
+	})
+
+	return c
+}
+
+// `--force-recreate` recreates the containers with `up` even if
+// nothing about them has changed
+func (c *DockerComposeCommandBuilder) ForceRecreate() *DockerComposeCommandBuilder {
+	c.Segments = append(c.Segments, Segment{
+		Priority: 1000,
+		Key:      "--force-recreate",
+		Value:    "",
+	})
+
+	return c
+}
+
+// `--no-deps` doesn't start the services the given ones depend
+// on, for `up` and `run`
+func (c *DockerComposeCommandBuilder) NoDeps() *DockerComposeCommandBuilder {
+	c.Segments = append(c.Segments, Segment{
+		Priority: 1000,
+		Key:      "--no-deps",
+		Value:    "",
+	})
+
+	return c
+}
+
+// `--rm` removes the container once `run` is done with it
+func (c *DockerComposeCommandBuilder) AutoRemove() *DockerComposeCommandBuilder {
+	c.Segments = append(c.Segments, Segment{
+		Priority: 1000,
+		Key:      "--rm",
+		Value:    "",
+	})
+
+	return c
+}
+
+// `--timeout` how many seconds to wait for the containers to stop
+// before killing them, for `up`, `down`, `stop`, and `restart`
+func (c *DockerComposeCommandBuilder) Timeout(seconds int) *DockerComposeCommandBuilder {
+	c.Segments = append(c.Segments, Segment{
+		Priority: 1000,
+		Key:      "--timeout",
+		Value:    strconv.Itoa(seconds),
+	})
+
+	return c
+}
+
+// `--host` The docker daemon to run the command against.
+// Nothing is added for an empty host, so the default one is used.
+func (c *DockerComposeCommandBuilder) Host(host string) *DockerComposeCommandBuilder {
+	if host == "" {
+		return c
+	}
+
+	c.Segments = append(c.Segments, Segment{
+		Priority: -10,
+		Key:      "--host",
+		Value:    host,
+	})
+
+	return c
+}
+
 // Creates a new instance of a Docker Compose Builder which can
 // be used to dynamically build a docker-compose command.
 func DockerComposeCommand() *DockerComposeCommandBuilder {
 	return &DockerComposeCommandBuilder{
-		Command: "docker compose",
+		Command: "docker",
 		Segments: []Segment{
-			{},
+			{Key: "compose"},
 		},
 		Unique: map[int]Segment{},
 	}
 }
 
 // Interface implementation
-func (c *DockerComposeCommandBuilder) Build() string {
+func (c *DockerComposeCommandBuilder) Args() []string {
 	// Get all the unique segments into an array as well
 	segments := []Segment{}
 	for _, segment := range c.Unique {
@@ -140,5 +383,10 @@ func (c *DockerComposeCommandBuilder) Build() string {
 	// Merge the unique segments with the actual segments
 	segments = append(segments, c.Segments...)
 
-	return BuildCommand(c.Command, segments...)
+	return BuildArgs(c.Command, segments...)
+}
+
+// Interface implementation
+func (c *DockerComposeCommandBuilder) Build() string {
+	return Quote(c.Args())
 }
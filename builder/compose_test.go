@@ -57,3 +57,238 @@ func TestComposeCommandWithMultipleServices(t *testing.T) {
 		t.Errorf("Expected %s, got %s", expected, cmd)
 	}
 }
+
+func TestComposeCommandWithHost(t *testing.T) {
+	cmd := DockerComposeCommand().
+		File("docker-compose.yml").
+		Host("ssh://dev@remote").
+		Up().
+		Build()
+
+	expected := "docker --host ssh://dev@remote compose -f docker-compose.yml up"
+
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
+
+func TestComposeCommandWithEmptyHost(t *testing.T) {
+	cmd := DockerComposeCommand().
+		File("docker-compose.yml").
+		Host("").
+		Up().
+		Build()
+
+	expected := "docker compose -f docker-compose.yml up"
+
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
+
+func TestComposeCommandWithServicesAtOnce(t *testing.T) {
+	cmd := DockerComposeCommand().
+		File("docker-compose.yml").
+		Service("web", "db").
+		Stop().
+		Build()
+
+	expected := "docker compose -f docker-compose.yml stop web db"
+
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
+
+func TestComposeCommandPs(t *testing.T) {
+	cmd := DockerComposeCommand().
+		File("docker-compose.yml").
+		Ps().
+		Build()
+
+	expected := "docker compose -f docker-compose.yml ps"
+
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
+
+func TestComposeCommandPull(t *testing.T) {
+	cmd := DockerComposeCommand().
+		File("docker-compose.yml").
+		Service("web").
+		Pull().
+		Build()
+
+	expected := "docker compose -f docker-compose.yml pull web"
+
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
+
+func TestComposeCommandBuildImages(t *testing.T) {
+	cmd := DockerComposeCommand().
+		File("docker-compose.yml").
+		Service("web", "worker").
+		BuildImages().
+		Build()
+
+	expected := "docker compose -f docker-compose.yml build web worker"
+
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
+
+func TestComposeCommandConfig(t *testing.T) {
+	cmd := DockerComposeCommand().
+		File("docker-compose.yml").
+		Config().
+		Build()
+
+	expected := "docker compose -f docker-compose.yml config"
+
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
+
+func TestComposeCommandExec(t *testing.T) {
+	cmd := DockerComposeCommand().
+		File("docker-compose.yml").
+		Invoke("rails", "db:migrate").
+		Service("web").
+		Exec().
+		Build()
+
+	expected := "docker compose -f docker-compose.yml exec web rails db:migrate"
+
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
+
+func TestComposeCommandRun(t *testing.T) {
+	cmd := DockerComposeCommand().
+		File("docker-compose.yml").
+		Service("web").
+		Run().
+		AutoRemove().
+		NoDeps().
+		Invoke("sh", "-c", "echo hi").
+		Build()
+
+	expected := "docker compose -f docker-compose.yml run --rm --no-deps web sh -c 'echo hi'"
+
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
+
+func TestComposeCommandInvokeOverwrites(t *testing.T) {
+	cmd := DockerComposeCommand().
+		Service("web").
+		Exec().
+		Invoke("aaa").
+		Invoke("bbb").
+		Build()
+
+	expected := "docker compose exec web bbb"
+
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
+
+func TestComposeCommandLogs(t *testing.T) {
+	cmd := DockerComposeCommand().
+		File("docker-compose.yml").
+		Service("web").
+		Logs().
+		Build()
+
+	expected := "docker compose -f docker-compose.yml logs web"
+
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
+
+func TestComposeCommandRm(t *testing.T) {
+	cmd := DockerComposeCommand().
+		File("docker-compose.yml").
+		Service("web").
+		Rm().
+		Build()
+
+	expected := "docker compose -f docker-compose.yml rm web"
+
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
+
+func TestComposeCommandSubcommandsCompete(t *testing.T) {
+	cmd := DockerComposeCommand().
+		File("docker-compose.yml").
+		Ps().
+		Logs().
+		Pull().
+		Build()
+
+	expected := "docker compose -f docker-compose.yml pull"
+
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
+
+func TestComposeCommandWithProjectNameAndProfiles(t *testing.T) {
+	cmd := DockerComposeCommand().
+		ProjectName("carbon").
+		File("docker-compose.yml").
+		Profile("debug").
+		Profile("tools").
+		Up().
+		Build()
+
+	expected := "docker compose --project-name carbon -f docker-compose.yml --profile debug --profile tools up"
+
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
+
+func TestComposeCommandUpFlags(t *testing.T) {
+	cmd := DockerComposeCommand().
+		File("docker-compose.yml").
+		Service("web").
+		Up().
+		Background().
+		ForceRecreate().
+		RemoveOrphans().
+		NoDeps().
+		Build()
+
+	expected := "docker compose -f docker-compose.yml up -d --force-recreate --remove-orphans --no-deps web"
+
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
+
+func TestComposeCommandWithTimeout(t *testing.T) {
+	cmd := DockerComposeCommand().
+		File("docker-compose.yml").
+		Stop().
+		Timeout(30).
+		Service("web").
+		Build()
+
+	expected := "docker compose -f docker-compose.yml stop --timeout 30 web"
+
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
@@ -0,0 +1,85 @@
+package builder
+
+// Command builder for a non interactive `docker exec` command.
+//
+// Supported segments:
+// - `host`: What docker daemon the command should run against.
+// - `container`: The container to run the command in.
+// - `command`: The command to run.
+type DockerExecCommandBuilder struct {
+	Command  string
+	Segments []Segment
+	Unique   map[int]Segment
+}
+
+// The container ID or name to run the command in.
+func (c *DockerExecCommandBuilder) Container(container string) *DockerExecCommandBuilder {
+	c.Segments = append(c.Segments, Segment{
+		Priority: 10,
+		Key:      container,
+		Value:    "",
+	})
+
+	return c
+}
+
+// The command to run within the provided container, along
+// with its arguments. This is unique, only the last one is ever run.
+func (c *DockerExecCommandBuilder) Run(command ...string) *DockerExecCommandBuilder {
+	segment := Segment{
+		Priority: 999,
+		Args:     command,
+	}
+
+	c.Unique[segment.Priority] = segment
+
+	return c
+}
+
+// `--host` The docker daemon to run the command against.
+// Nothing is added for an empty host, so the default one is used.
+func (c *DockerExecCommandBuilder) Host(host string) *DockerExecCommandBuilder {
+	if host == "" {
+		return c
+	}
+
+	c.Segments = append(c.Segments, Segment{
+		Priority: -10,
+		Key:      "--host",
+		Value:    host,
+	})
+
+	return c
+}
+
+// Creates a new instance of a Docker Exec Builder which can
+// be used to dynamically build a docker exec command that
+// doesn't need a terminal, so it can run alongside others.
+func DockerExecCommand() *DockerExecCommandBuilder {
+	return &DockerExecCommandBuilder{
+		Command: "docker",
+		Segments: []Segment{
+			{Key: "exec"},
+		},
+		Unique: map[int]Segment{},
+	}
+}
+
+// Interface implementation
+func (c *DockerExecCommandBuilder) Args() []string {
+	// Get all the unique segments into an array
+	segments := []Segment{}
+	for _, segment := range c.Unique {
+		segments = append(segments, segment)
+	}
+
+	// Merge the unique segments with the rest
+	segments = append(segments, c.Segments...)
+
+	return BuildArgs(c.Command, segments...)
+}
+
+// Interface implementation
+func (c *DockerExecCommandBuilder) Build() string {
+	return Quote(c.Args())
+}
@@ -0,0 +1,60 @@
+package builder
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExecCommand(t *testing.T) {
+	cmd := DockerExecCommand().
+		Container("my-fancy-name").
+		Run("echo", "hello").
+		Build()
+
+	expected := "docker exec my-fancy-name echo hello"
+
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
+
+func TestExecCommandOverwrites(t *testing.T) {
+	cmd := DockerExecCommand().
+		Run("aaa").
+		Container("my-fancy-name").
+		Run("bbb").
+		Build()
+
+	expected := "docker exec my-fancy-name bbb"
+
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
+
+func TestExecCommandWithHost(t *testing.T) {
+	cmd := DockerExecCommand().
+		Host("tcp://10.0.0.2:2375").
+		Container("thing").
+		Run("ls").
+		Build()
+
+	expected := "docker --host tcp://10.0.0.2:2375 exec thing ls"
+
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
+
+func TestExecCommandKeepsEveryArgumentSeparate(t *testing.T) {
+	args := DockerExecCommand().
+		Container("thing").
+		Run("sh", "-c", "echo $HOME && ls").
+		Args()
+
+	expected := []string{"docker", "exec", "thing", "sh", "-c", "echo $HOME && ls"}
+
+	if strings.Join(args, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected %q, got %q", expected, args)
+	}
+}
@@ -3,7 +3,12 @@ package builder
 // Command builder for a `docker logs` command.
 //
 // Supported segments:
+// - `host`: What docker daemon the command should run against.
 // - `follow`: Follow the logs.
+// - `tail`: How many lines to show from the end of the logs.
+// - `since`: Only show the logs since a given time.
+// - `until`: Only show the logs until a given time.
+// - `timestamps`: Show the time of every line.
 // - `container`: The container to get the logs from.
 type DockerLogsCommandBuilder struct {
 	Command  string
@@ -21,6 +26,53 @@ func (c *DockerLogsCommandBuilder) Follow() *DockerLogsCommandBuilder {
 	return c
 }
 
+// `--tail` How many lines to show from the end of the logs,
+// either a number or `all`.
+func (c *DockerLogsCommandBuilder) Tail(lines string) *DockerLogsCommandBuilder {
+	c.Segments = append(c.Segments, Segment{
+		Priority: 10,
+		Key:      "--tail",
+		Value:    lines,
+	})
+
+	return c
+}
+
+// `--since` Only show the logs since the given time, either a
+// timestamp or a duration relative to now, like `10m`.
+func (c *DockerLogsCommandBuilder) Since(since string) *DockerLogsCommandBuilder {
+	c.Segments = append(c.Segments, Segment{
+		Priority: 10,
+		Key:      "--since",
+		Value:    since,
+	})
+
+	return c
+}
+
+// `--until` Only show the logs until the given time, either a
+// timestamp or a duration relative to now, like `10m`.
+func (c *DockerLogsCommandBuilder) Until(until string) *DockerLogsCommandBuilder {
+	c.Segments = append(c.Segments, Segment{
+		Priority: 10,
+		Key:      "--until",
+		Value:    until,
+	})
+
+	return c
+}
+
+// `-t` Show the time of every line.
+func (c *DockerLogsCommandBuilder) Timestamps() *DockerLogsCommandBuilder {
+	c.Segments = append(c.Segments, Segment{
+		Priority: 10,
+		Key:      "-t",
+		Value:    "",
+	})
+
+	return c
+}
+
 // The container to get the logs from.
 func (c *DockerLogsCommandBuilder) Container(name string) *DockerLogsCommandBuilder {
 	c.Segments = append(c.Segments, Segment{
@@ -32,16 +84,39 @@ func (c *DockerLogsCommandBuilder) Container(name string) *DockerLogsCommandBuil
 	return c
 }
 
+// `--host` The docker daemon to run the command against.
+// Nothing is added for an empty host, so the default one is used.
+func (c *DockerLogsCommandBuilder) Host(host string) *DockerLogsCommandBuilder {
+	if host == "" {
+		return c
+	}
+
+	c.Segments = append(c.Segments, Segment{
+		Priority: -10,
+		Key:      "--host",
+		Value:    host,
+	})
+
+	return c
+}
+
 // Creates a new instance of a Docker Logs Builder which can
 // be used to dynamically build a docker logs command.
 func DockerLogsCommand() *DockerLogsCommandBuilder {
 	return &DockerLogsCommandBuilder{
-		Command:  "docker logs",
-		Segments: []Segment{},
+		Command: "docker",
+		Segments: []Segment{
+			{Key: "logs"},
+		},
 	}
 }
 
+// Interface implementation
+func (c *DockerLogsCommandBuilder) Args() []string {
+	return BuildArgs(c.Command, c.Segments...)
+}
+
 // Interface implementation
 func (c *DockerLogsCommandBuilder) Build() string {
-	return BuildCommand(c.Command, c.Segments...)
+	return Quote(c.Args())
 }
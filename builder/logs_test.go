@@ -26,3 +26,33 @@ func TestLogsCommandNoFollow(t *testing.T) {
 		t.Errorf("Expected %s, got %s", expected, cmd)
 	}
 }
+
+func TestLogsCommandWithHost(t *testing.T) {
+	cmd := DockerLogsCommand().
+		Host("tcp://10.0.0.2:2375").
+		Container("thing").
+		Build()
+
+	expected := "docker --host tcp://10.0.0.2:2375 logs thing"
+
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
+
+func TestLogsCommandWithEverything(t *testing.T) {
+	cmd := DockerLogsCommand().
+		Container("thing").
+		Follow().
+		Tail("100").
+		Since("10m").
+		Until("2022-01-01T10:00:00").
+		Timestamps().
+		Build()
+
+	expected := "docker logs -f --tail 100 --since 10m --until 2022-01-01T10:00:00 -t thing"
+
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
@@ -3,6 +3,7 @@ package builder
 // Command builder for a `docker exec` command.
 //
 // Supported segments:
+// - `host`: What docker daemon the command should run against.
 // - `container`: The container to be used.
 // - `shell`: The shell to be used.
 type DockerShellCommandBuilder struct {
@@ -22,16 +23,16 @@ func (c *DockerShellCommandBuilder) Container(container string) *DockerShellComm
 	return c
 }
 
-// A command to be executed within the provided container.
-// This is usually used as a shell so `/bin/bash` is used by default.
+// A command to be executed within the provided container, along
+// with its arguments. This is usually used as a shell so `/bin/bash`
+// is used by default.
 //
 // There's nothing that stops it from being used with other commands if
 // execution on multiple containers might be needed someday.
-func (c *DockerShellCommandBuilder) Shell(shell string) *DockerShellCommandBuilder {
+func (c *DockerShellCommandBuilder) Shell(shell ...string) *DockerShellCommandBuilder {
 	segment := Segment{
 		Priority: 999,
-		Key:      shell,
-		Value:    "",
+		Args:     shell,
 	}
 
 	c.Unique[segment.Priority] = segment
@@ -39,18 +40,37 @@ func (c *DockerShellCommandBuilder) Shell(shell string) *DockerShellCommandBuild
 	return c
 }
 
+// `--host` The docker daemon to run the command against.
+// Nothing is added for an empty host, so the default one is used.
+func (c *DockerShellCommandBuilder) Host(host string) *DockerShellCommandBuilder {
+	if host == "" {
+		return c
+	}
+
+	c.Segments = append(c.Segments, Segment{
+		Priority: -10,
+		Key:      "--host",
+		Value:    host,
+	})
+
+	return c
+}
+
 // Creates a new instance of a Docker Exec Builder which can
 // be used to dynamically build a docker exec command.
 func DockerShellCommand() *DockerShellCommandBuilder {
 	return &DockerShellCommandBuilder{
-		Command:  "docker exec -it",
-		Segments: []Segment{},
-		Unique:   map[int]Segment{},
+		Command: "docker",
+		Segments: []Segment{
+			{Key: "exec"},
+			{Key: "-it"},
+		},
+		Unique: map[int]Segment{},
 	}
 }
 
 // Interface implementation
-func (c *DockerShellCommandBuilder) Build() string {
+func (c *DockerShellCommandBuilder) Args() []string {
 	// Get all the unique segments into an array
 	segments := []Segment{}
 	for _, segment := range c.Unique {
@@ -60,5 +80,10 @@ func (c *DockerShellCommandBuilder) Build() string {
 	// Merge the unique segments with the rest
 	segments = append(segments, c.Segments...)
 
-	return BuildCommand(c.Command, segments...)
+	return BuildArgs(c.Command, segments...)
+}
+
+// Interface implementation
+func (c *DockerShellCommandBuilder) Build() string {
+	return Quote(c.Args())
 }
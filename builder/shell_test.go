@@ -28,3 +28,30 @@ func TestShellCommandOverwrites(t *testing.T) {
 		t.Errorf("Expected %s, got %s", expected, cmd)
 	}
 }
+
+func TestShellCommandWithHost(t *testing.T) {
+	cmd := DockerShellCommand().
+		Host("tcp://10.0.0.2:2375").
+		Container("thing").
+		Shell("/bin/sh").
+		Build()
+
+	expected := "docker --host tcp://10.0.0.2:2375 exec -it thing /bin/sh"
+
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
+
+func TestShellCommandWithArguments(t *testing.T) {
+	cmd := DockerShellCommand().
+		Container("thing").
+		Shell("bash", "-l").
+		Build()
+
+	expected := "docker exec -it thing bash -l"
+
+	if cmd != expected {
+		t.Errorf("Expected %s, got %s", expected, cmd)
+	}
+}
@@ -86,3 +86,34 @@ func TestYamlParsingOfMultipleDocuments(t *testing.T) {
 		t.Errorf("Expected 2 fields, got %d", len(config["test-db"].FullContents))
 	}
 }
+
+// Note that this is indented with spaces as well.
+var buildDocument = `
+short:
+    build: ./short
+---
+full:
+    build:
+        context: ./full
+        dockerfile: Dockerfile.dev
+        args:
+            - TOKEN
+            - MODE=dev
+`
+
+func TestYamlParsingOfBuildSections(t *testing.T) {
+	config := documents([]byte(buildDocument), "filename")
+
+	if config["short"].Build.Context != "./short" {
+		t.Errorf("Expected the short build context to be './short', got '%s'", config["short"].Build.Context)
+	}
+
+	full := config["full"].Build
+	if full.Context != "./full" || full.Dockerfile != "Dockerfile.dev" {
+		t.Errorf("Expected the full build section to be parsed, got %v", full)
+	}
+
+	if len(full.Args) != 2 || full.Args["TOKEN"] != "" || full.Args["MODE"] != "dev" {
+		t.Errorf("Expected the build args to be parsed, got %v", full.Args)
+	}
+}
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"co2/builder"
+	"co2/docker"
+	"co2/helpers"
+	"co2/printer"
+	"co2/runner"
+	"co2/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	buildCmd = &cobra.Command{
+		Use:   "build <services>",
+		Short: "Builds the images of the provided services",
+		Args:  cobra.MinimumNArgs(1),
+		Run:   locked(execBuild),
+	}
+)
+
+// Builds the images of all the provided services at once,
+// each one with its output labelled by the service name.
+func execBuild(cmd *cobra.Command, args []string) {
+	configs := fs.Services()
+	chosen := types.CarbonConfig{}
+
+	for _, service := range args {
+		found, ok := configs[service]
+		if !ok {
+			printer.Extra(printer.Red, "No carbon file found for: "+service)
+			continue
+		}
+
+		chosen[service] = found
+	}
+
+	building := builds(chosen)
+	for name := range chosen {
+		if _, ok := building[name]; !ok {
+			printer.Extra(printer.Grey, "Nothing to build for: "+name)
+		}
+	}
+
+	if len(building) == 0 {
+		printer.Info(printer.Cyan, "BUILD", "Nothing to build", "")
+		return
+	}
+
+	host, err := startHost(building)
+	if err != nil {
+		printer.Error("ERROR", err.Error(), "")
+		printer.Extra(printer.Red, "Use `--host` or `--context` to pick one", "Aborting")
+		return
+	}
+	docker.UseHost(host)
+
+	if failed := build(building); len(failed) > 0 {
+		printer.Error("ERROR", "couldn't build:", strings.Join(failed, ", "))
+		os.Exit(1)
+	}
+}
+
+// Returns only the given services that have
+// an image to build.
+func builds(choices types.CarbonConfig) types.CarbonConfig {
+	building := types.CarbonConfig{}
+
+	for name, service := range choices {
+		if service.Builds() {
+			building[name] = service
+		}
+	}
+
+	return building
+}
+
+// Builds the images of all the given services in parallel
+// and waits for all of them to be done.
+//
+// The services whose images failed to build are returned.
+func build(choices types.CarbonConfig) []string {
+	commands := buildCommands(choices)
+
+	names := []string{}
+	for _, command := range commands {
+		names = append(names, command.Label)
+	}
+
+	printer.Info(printer.Green, "BUILD", "Building images for:", strings.Join(names, ", "))
+	codes := runner.Execute(commands...)
+
+	failed := []string{}
+	for i, code := range codes {
+		if code != 0 {
+			failed = append(failed, names[i])
+		}
+	}
+
+	return failed
+}
+
+// Generates the docker build commands for the given services,
+// tagging every image with the one the service is going to run.
+func buildCommands(choices types.CarbonConfig) []types.Command {
+	names := []string{}
+	for name := range choices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	commands := []types.Command{}
+
+	for _, name := range names {
+		service := choices[name]
+		context := service.BuildContext()
+
+		command := builder.DockerBuildCommand().
+			Host(docker.DefaultHost()).
+			Tag(service.ImageTag()).
+			Path(context)
+
+		// Docker looks for the file from where it runs, not from the context
+		if service.Build.Dockerfile != "" {
+			command.File(filepath.Join(context, service.Build.Dockerfile))
+		}
+
+		args, env := buildArgs(service)
+		for _, arg := range args {
+			command.BuildArg(arg)
+		}
+
+		commands = append(commands, types.Command{
+			Text:  command.Build(),
+			Args:  command.Args(),
+			Env:   env,
+			Label: name,
+		})
+	}
+
+	return commands
+}
+
+// Works out the build arguments of the given service.
+//
+// Only the arguments the service declares are passed along. Their
+// values can refer to the variables from the environment files of
+// its store, or to the ones of the shell, like they can in a compose
+// file. Arguments without a value take the one of the variable with
+// the same name, so that things like tokens only have to be set up
+// once per store.
+//
+// Those are passed by name only, with their values in the returned
+// environment of the command instead, so they never show up in the
+// command itself, or in the process list.
+func buildArgs(service types.CarbonService) ([]string, []string) {
+	env := map[string]string{}
+
+	if service.Store != nil {
+		for _, file := range service.Store.Envs {
+			variables, err := helpers.ReadEnvFile(file)
+			if err != nil {
+				printer.Extra(printer.Yellow, "Couldn't read `"+file+"` for the build arguments, skipping it")
+				continue
+			}
+
+			for key, value := range variables {
+				env[key] = value
+			}
+		}
+	}
+
+	lookup := func(key string) string {
+		if value, ok := env[key]; ok {
+			return value
+		}
+
+		return os.Getenv(key)
+	}
+
+	keys := []string{}
+	for key := range service.Build.Args {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	args := []string{}
+	environment := []string{}
+
+	for _, key := range keys {
+		value := service.Build.Args[key]
+
+		if value == "" {
+			args = append(args, key)
+
+			// The shell ones are already there
+			if stored, ok := env[key]; ok {
+				environment = append(environment, key+"="+stored)
+			}
+
+			continue
+		}
+
+		args = append(args, key+"="+os.Expand(value, lookup))
+	}
+
+	return args, environment
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"co2/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/4khara/replica"
+)
+
+func TestBuildsOnlyReturnsServicesWithABuildSection(t *testing.T) {
+	building := builds(mockBuildConfig())
+
+	if len(building) != 1 {
+		t.Fatal("builds should only return the services that build, got", building)
+	}
+
+	if _, ok := building["foo"]; !ok {
+		t.Error("builds should return foo, got", building)
+	}
+}
+
+func TestBuildCommandsTagTheImagesOfTheServices(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	service := mockBuildConfig()["foo"]
+	service.Store = nil
+
+	commands := buildCommands(types.CarbonConfig{"foo": service})
+
+	if len(commands) != 1 || commands[0].Label != "foo" {
+		t.Fatal("buildCommands should generate one labelled command per service, got", commands)
+	}
+
+	expected := "docker build -f /code/foo/app/Dockerfile.dev -t co2/foo:latest /code/foo/app"
+	if commands[0].Text != expected {
+		t.Errorf("Expected %s, got %s", expected, commands[0].Text)
+	}
+}
+
+func TestBuildArgsComeFromTheStoreEnvironment(t *testing.T) {
+	env := filepath.Join(t.TempDir(), ".env")
+	os.WriteFile(env, []byte("TOKEN=secret\nREGION=eu\nUNUSED=nope\n"), 0644)
+
+	service := types.CarbonService{
+		Store: &types.Store{Envs: []string{env}},
+		Build: types.BuildConfig{
+			Context: ".",
+			Args: map[string]string{
+				"REGION": "us",
+				"URL":    "https://${REGION}.example.com",
+				"TOKEN":  "",
+			},
+		},
+	}
+
+	args, environment := buildArgs(service)
+	expected := "REGION=us,TOKEN,URL=https://eu.example.com"
+
+	if strings.Join(args, ",") != expected {
+		t.Errorf("Expected %s, got %s", expected, strings.Join(args, ","))
+	}
+
+	if strings.Join(environment, ",") != "TOKEN=secret" {
+		t.Error("buildArgs should only pass the values of bare arguments through the environment, got", environment)
+	}
+}
+
+func TestBuildCommandsKeepSecretsOutOfTheCommand(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	env := filepath.Join(t.TempDir(), ".env")
+	os.WriteFile(env, []byte("TOKEN=secret\n"), 0644)
+
+	service := mockBuildConfig()["foo"]
+	service.Store = &types.Store{Envs: []string{env}}
+	service.Build.Args = map[string]string{"TOKEN": ""}
+
+	commands := buildCommands(types.CarbonConfig{"foo": service})
+
+	if strings.Contains(strings.Join(commands[0].Args, " "), "secret") {
+		t.Error("buildCommands shouldn't put secrets in the command, got", commands[0].Args)
+	}
+
+	if strings.Join(commands[0].Env, ",") != "TOKEN=secret" {
+		t.Error("buildCommands should pass the secrets through the environment, got", commands[0].Env)
+	}
+}
+
+func TestBuildReturnsTheServicesThatFailed(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	service := mockBuildConfig()["foo"]
+	service.Store = nil
+
+	replica.Mocks.SetReturnValues("Execute", 1)
+
+	failed := build(types.CarbonConfig{"foo": service})
+
+	if strings.Join(failed, ",") != "foo" {
+		t.Error("build should return the services that failed to build, got", failed)
+	}
+}
+
+func TestBuildReturnsNothingWhenEverythingBuilds(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	service := mockBuildConfig()["foo"]
+	service.Store = nil
+
+	if failed := build(types.CarbonConfig{"foo": service}); len(failed) != 0 {
+		t.Error("build shouldn't return anything when every image builds, got", failed)
+	}
+}
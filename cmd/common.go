@@ -18,8 +18,8 @@ type impl struct{}
 // the carbon services that are defined within those stores.
 //
 // This will never to too deep into the stores when looking
-// for services since we want it to be fast. Usually a depth of 2
-// is enough.
+// for services since we want it to be fast. Each store defines
+// its own depth, usually a depth of 2 is enough.
 //
 // Each of the returned configurations will have the store
 // they belong to injected as well so they can retrieve
@@ -29,7 +29,9 @@ func (i *impl) Services() types.CarbonConfig {
 	configs := types.CarbonConfig{}
 
 	for _, store := range stores {
-		files := carbon.Configurations(store.Path, 2)
+		// Every service needs a pointer to its own store
+		store := store
+		files := carbon.Configurations(store.Path, store.Depth)
 
 		for k, v := range files {
 			v.Store = &store
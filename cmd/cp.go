@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"co2/docker"
+	"co2/printer"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	cpCmd = &cobra.Command{
+		Use:   "cp <source> <destinations>",
+		Short: "Copies files between the local machine and containers",
+		Args:  cobra.MinimumNArgs(2),
+		Run:   execCp,
+	}
+)
+
+// A path on either side of a copy. The container is
+// empty when the path is a local one.
+type copyTarget struct {
+	Ident string // What the container was referenced by
+	Path  string // The path within the container, or the local one
+}
+
+// Copies files between the local machine and containers, the
+// same way `docker cp` does, with containers referenced the same
+// way `shell` references them (`<container>:<path>`).
+//
+// A local path can be copied into as many containers as given at
+// once, which is handy for dropping a fixture into all the workers.
+// Copying out of a container only ever goes to a single local path.
+//
+// If anything can't be copied, this exits with an error once
+// everything else has been.
+func execCp(cmd *cobra.Command, args []string) {
+	source := parseCopyTarget(args[0])
+	destinations := []copyTarget{}
+
+	for _, arg := range args[1:] {
+		destinations = append(destinations, parseCopyTarget(arg))
+	}
+
+	if err := validateCopy(source, destinations); err != "" {
+		printer.Error("ERROR", err, "")
+		os.Exit(1)
+	}
+
+	if source.Ident != "" {
+		if !copyFrom(source, destinations[0].Path) {
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	failed := false
+
+	for _, destination := range destinations {
+		if !copyTo(source.Path, destination) {
+			failed = true
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// Splits an argument into the container and the path within
+// it, if it refers to a container at all.
+//
+// Paths that start with a `/` or a `.` are always local, so that
+// local paths with colons in them can still be copied.
+func parseCopyTarget(arg string) copyTarget {
+	if strings.HasPrefix(arg, "/") || strings.HasPrefix(arg, ".") || filepath.VolumeName(arg) != "" {
+		return copyTarget{Path: arg}
+	}
+
+	parts := strings.SplitN(arg, ":", 2)
+	if len(parts) == 1 {
+		return copyTarget{Path: arg}
+	}
+
+	return copyTarget{Ident: parts[0], Path: parts[1]}
+}
+
+// Makes sure the copy goes between the local machine and
+// containers, and never anywhere else, describing what's wrong
+// with it if it doesn't.
+func validateCopy(source copyTarget, destinations []copyTarget) string {
+	if source.Ident != "" {
+		if len(destinations) != 1 || destinations[0].Ident != "" {
+			return "copying out of a container only works into a single local path"
+		}
+
+		return ""
+	}
+
+	for _, destination := range destinations {
+		if destination.Ident == "" {
+			return "either the source or the destinations have to be `<container>:<path>`"
+		}
+	}
+
+	return ""
+}
+
+// Copies the given path out of its container into
+// the given local path, and says how it went.
+func copyFrom(source copyTarget, dst string) bool {
+	found := findContainer(source.Ident)
+	if found.Name == "" {
+		printer.Error("ERROR", "container not found:", source.Ident)
+		return false
+	}
+
+	if err := docker.CopyFrom(found.Host, found.Name, source.Path, dst); err != nil {
+		printer.Error("ERROR", "couldn't copy from "+found.Name+":", err.Error())
+		return false
+	}
+
+	printer.Extra(printer.Green, "Copied `"+found.Name+":"+source.Path+"` to `"+dst+"`")
+	return true
+}
+
+// Copies the given local path into its destination
+// container, and says how it went.
+func copyTo(src string, destination copyTarget) bool {
+	found := findContainer(destination.Ident)
+	if found.Name == "" {
+		printer.Error("ERROR", "container not found:", destination.Ident)
+		return false
+	}
+
+	if err := docker.CopyTo(found.Host, found.Name, src, destination.Path); err != nil {
+		printer.Error("ERROR", "couldn't copy into "+found.Name+":", err.Error())
+		return false
+	}
+
+	printer.Extra(printer.Green, "Copied `"+src+"` to `"+found.Name+":"+destination.Path+"`")
+	return true
+}
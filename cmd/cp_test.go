@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"co2/database"
+	"co2/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/4khara/replica"
+)
+
+func TestParseCopyTargetSplitsContainerPaths(t *testing.T) {
+	cases := map[string]copyTarget{
+		"worker:/app/fixture.json": {Ident: "worker", Path: "/app/fixture.json"},
+		"./local:file":             {Path: "./local:file"},
+		"/absolute/path":           {Path: "/absolute/path"},
+		"relative.txt":             {Path: "relative.txt"},
+	}
+
+	for arg, expected := range cases {
+		if parsed := parseCopyTarget(arg); parsed != expected {
+			t.Errorf("Expected %s to become %v, got %v", arg, expected, parsed)
+		}
+	}
+}
+
+func TestValidateCopyOnlyAllowsCopiesBetweenLocalAndContainers(t *testing.T) {
+	local := copyTarget{Path: "./file"}
+	remote := copyTarget{Ident: "worker", Path: "/app"}
+
+	if err := validateCopy(local, []copyTarget{remote, remote}); err != "" {
+		t.Error("Copying into several containers should be allowed, got", err)
+	}
+
+	if err := validateCopy(remote, []copyTarget{local}); err != "" {
+		t.Error("Copying out of a container should be allowed, got", err)
+	}
+
+	if validateCopy(remote, []copyTarget{local, local}) == "" {
+		t.Error("Copying out of a container into several paths shouldn't be allowed")
+	}
+
+	if validateCopy(local, []copyTarget{local}) == "" {
+		t.Error("Copying between local paths shouldn't be allowed")
+	}
+}
+
+func TestCopyToFindsContainersByServiceName(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	database.AddContainer(types.Container{
+		Image:       "image1",
+		Name:        "worker-abc",
+		Uid:         "uid1",
+		ServiceName: "worker",
+	})
+
+	src := filepath.Join(t.TempDir(), "fixture.json")
+	os.WriteFile(src, []byte("{}"), 0644)
+
+	if !copyTo(src, copyTarget{Ident: "worker", Path: "/app"}) {
+		t.Fatal("copyTo should copy into the container of the service")
+	}
+
+	if replica.Mocks.GetCallParams("CopyTo")[0][0] != "worker-abc" {
+		t.Error("copyTo should copy into the found container, got", replica.Mocks.GetCallParams("CopyTo")[0][0])
+	}
+
+	if copyTo(src, copyTarget{Ident: "nope", Path: "/app"}) {
+		t.Error("copyTo should fail for containers that don't exist")
+	}
+}
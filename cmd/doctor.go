@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"co2/database"
+	"co2/docker"
+	"co2/helpers"
+	"co2/printer"
+	"co2/types"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkDatabase bool
+	repair        bool
+	rebuild       bool
+
+	doctorCmd = &cobra.Command{
+		Use:   "doctor",
+		Short: "Checks carbon for problems and repairs them",
+		Run:   locked(execDoctor),
+	}
+)
+
+// Adds all the required flags
+func init() {
+	doctorCmd.Flags().BoolVar(&checkDatabase, "db", false, "check the integrity of the carbon database")
+	doctorCmd.Flags().BoolVar(&repair, "repair", false, "repair all the problems that are found")
+	doctorCmd.Flags().BoolVar(&rebuild, "rebuild", false, "rebuild the known containers from the labels of the running ones")
+}
+
+// Runs all the requested checks, or all of them if
+// nothing specific has been requested.
+func execDoctor(cmd *cobra.Command, args []string) {
+	all := !checkDatabase && !rebuild
+
+	if checkDatabase || all {
+		if !doctorDatabase(repair) {
+			os.Exit(1)
+		}
+	}
+
+	if rebuild {
+		rebuildContainers()
+	}
+}
+
+// Looks for problems in the database and reports all of
+// them, fixing them as well if we've been asked to.
+//
+// Returns whether or not the database is healthy once
+// everything is done.
+func doctorDatabase(repair bool) bool {
+	printer.Info(printer.Cyan, "DOCTOR", "Checking the database", helpers.DatabaseFile())
+
+	problems := database.Diagnose()
+	if len(problems) == 0 {
+		printer.Extra(printer.Green, "No problems found")
+		return true
+	}
+
+	printer.Extra(printer.Yellow, problems...)
+
+	if !repair {
+		printer.Extra(printer.Cyan, "Run again with `--repair` to fix them")
+		return false
+	}
+
+	printer.Info(printer.Green, "REPAIR", "Repairing the database", "")
+	printer.Extra(printer.Green, database.Repair()...)
+
+	remaining := database.Diagnose()
+	if len(remaining) != 0 {
+		printer.Error("ERROR", "Some problems could not be repaired", "")
+		printer.Extra(printer.Red, remaining...)
+		return false
+	}
+
+	printer.Extra(printer.Green, "All problems repaired")
+	return true
+}
+
+// Replaces everything the database knows about containers with
+// what docker knows about the containers that carbon started.
+//
+// Every container carbon starts is labelled with the service it
+// belongs to and where it came from, so even if the database gets
+// lost, or out of sync, the running containers can be tracked again.
+// Containers the database knows about which aren't running anymore
+// are forgotten.
+func rebuildContainers() {
+	printer.Info(printer.Cyan, "DOCTOR", "Rebuilding containers from docker labels", "")
+
+	stores := map[string]int64{}
+	for _, store := range database.Stores() {
+		stores[store.Uid] = store.Id
+	}
+
+	found := map[string]bool{}
+	for _, container := range docker.RunningContainers(types.LabelService) {
+		labels := container.Labels
+
+		container.ServiceName = labels[types.LabelService]
+		container.ComposeFile = labels[types.LabelComposeFile]
+		container.CarbonFile = labels[types.LabelCarbonFile]
+		container.StartArgs = strings.Fields(labels[types.LabelStartArgs])
+		container.StoreId = stores[labels[types.LabelStore]]
+
+		database.AddContainer(container)
+		found[container.Name] = true
+
+		printer.Extra(printer.Green, "Found '"+container.ServiceName+"' running as "+container.Name)
+	}
+
+	for _, container := range database.Containers() {
+		if found[container.Name] {
+			continue
+		}
+
+		database.DeleteContainer(container)
+		printer.Extra(printer.Yellow, "Forgot '"+container.ServiceName+"' since "+container.Name+" isn't running")
+	}
+
+	printer.Extra(printer.Green, fmt.Sprintf("%d running carbon containers", len(found)))
+}
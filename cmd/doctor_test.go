@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"co2/database"
+	"co2/types"
+	"context"
+	"testing"
+
+	"github.com/4khara/replica"
+	dockerTypes "github.com/docker/docker/api/types"
+)
+
+func TestDoctorDatabaseIsHealthyWithoutProblems(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	database.AddStore(types.Store{Uid: "uid1", Path: "path1"})
+
+	if !doctorDatabase(false) {
+		t.Error("doctorDatabase should report a healthy database when there are no problems")
+	}
+}
+
+func TestDoctorDatabaseRepairsProblems(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	db, _ := database.Get()
+
+	// Link some labels to a store that doesn't exist, which
+	// needs the foreign keys off on one specific connection
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statements := []string{
+		"PRAGMA foreign_keys = OFF;",
+		"INSERT INTO store_labels(store_id, key, value) VALUES(999999, 'team', 'core');",
+		"PRAGMA foreign_keys = ON;",
+	}
+
+	for _, statement := range statements {
+		if _, err := conn.ExecContext(context.Background(), statement); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	conn.Close()
+
+	if doctorDatabase(false) {
+		t.Error("doctorDatabase should report the labels of a missing store")
+	}
+
+	if !doctorDatabase(true) {
+		t.Error("doctorDatabase should repair the labels of a missing store")
+	}
+}
+
+func TestRebuildContainersRestoresThemFromLabels(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	store := database.AddStore(types.Store{Uid: "uid1", Path: "path1"})
+	database.AddContainer(types.Container{Name: "stale-container", ServiceName: "stale"})
+
+	replica.Mocks.SetReturnValues("RunningContainers", []dockerTypes.Container{
+		{
+			ID:    "1",
+			Image: "image1",
+			Names: []string{"/api-abcdefghij"},
+			Labels: map[string]string{
+				types.LabelService:     "api",
+				types.LabelStore:       "uid1",
+				types.LabelCarbonFile:  "/code/api/carbon.yml",
+				types.LabelComposeFile: "/compose.yml",
+				types.LabelStartArgs:   "api db",
+			},
+		},
+	})
+
+	rebuildContainers()
+
+	containers := database.Containers()
+	if len(containers) != 1 {
+		t.Fatal("rebuildContainers should only keep the running containers, got", len(containers))
+	}
+
+	container := containers[0]
+
+	if container.Name != "api-abcdefghij" || container.ServiceName != "api" {
+		t.Error("rebuildContainers should restore the service from the labels, got", container.ServiceName)
+	}
+
+	if container.StoreId != store.Id || container.ComposeFile != "/compose.yml" || container.CarbonFile != "/code/api/carbon.yml" {
+		t.Error("rebuildContainers should restore where the container came from, got", container)
+	}
+
+	if len(container.StartArgs) != 2 {
+		t.Error("rebuildContainers should restore the start arguments, got", container.StartArgs)
+	}
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"co2/builder"
+	"co2/printer"
+	"co2/runner"
+	"co2/types"
+	"os"
+	"strings"
+
+	"github.com/kballard/go-shellquote"
+	"github.com/spf13/cobra"
+)
+
+var (
+	execCommand string
+
+	execCmd = &cobra.Command{
+		Use:   "exec <containers> [-c command | -- command]",
+		Short: "Runs a command within all the provided containers at once",
+		Args:  cobra.MinimumNArgs(1),
+		Run:   execExec,
+	}
+)
+
+// Adds the required flags
+func init() {
+	execCmd.Flags().StringVarP(&execCommand, "command", "c", "", "the command to run, instead of everything after `--`")
+}
+
+// Runs the provided command in every one of the provided
+// containers in parallel, with the output of each labelled
+// by the container it came from.
+//
+// The containers are found the same way `shell` finds them, so
+// ids, container names, and service names all work. If any of
+// them can't be found, or the command fails in any of them, this
+// exits with an error once everything's done.
+func execExec(cmd *cobra.Command, args []string) {
+	idents, command, err := execArgs(args, cmd.ArgsLenAtDash(), execCommand)
+	if err != nil {
+		printer.Error("ERROR", "couldn't read the command:", err.Error())
+		os.Exit(1)
+	}
+
+	if len(command) == 0 {
+		printer.Error("ERROR", "no command to run", "")
+		printer.Extra(printer.Red, "Use `-c` or put the command after `--`")
+		return
+	}
+
+	if len(idents) == 0 {
+		printer.Error("ERROR", "no containers to run the command in", "")
+		return
+	}
+
+	commands, missing := generateExecCommands(idents, command)
+	for _, ident := range missing {
+		printer.Error("ERROR", "container not found:", ident)
+	}
+
+	codes := runner.Execute(commands...)
+
+	failed := []string{}
+	for i, code := range codes {
+		if code != 0 {
+			failed = append(failed, commands[i].Label)
+		}
+	}
+
+	if len(failed) > 0 {
+		printer.Error("ERROR", "the command failed in:", strings.Join(failed, ", "))
+	}
+
+	if len(failed) > 0 || len(missing) > 0 {
+		os.Exit(1)
+	}
+}
+
+// Separates the containers from the command to run in them.
+//
+// Everything after `--` is the command, if there's a dash at all,
+// and is run exactly as it was given. Otherwise it's whatever was
+// given with `-c`, split up the way a shell would split it.
+func execArgs(args []string, dash int, custom string) ([]string, []string, error) {
+	if dash >= 0 {
+		return args[:dash], args[dash:], nil
+	}
+
+	command, err := shellquote.Split(custom)
+	if err != nil {
+		return args, nil, err
+	}
+
+	return args, command, nil
+}
+
+// Generates a docker exec command for each of the provided
+// containers, labelled by the name of the container.
+//
+// The identifiers that don't belong to any container are
+// returned separately.
+func generateExecCommands(idents []string, command []string) ([]types.Command, []string) {
+	commands := []types.Command{}
+	missing := []string{}
+
+	for _, ident := range idents {
+		found := findContainer(ident)
+		if found.Name == "" {
+			missing = append(missing, ident)
+			continue
+		}
+
+		built := builder.DockerExecCommand().
+			Host(found.Host).
+			Container(found.Name).
+			Run(command...)
+
+		commands = append(commands, types.Command{
+			Text:  built.Build(),
+			Args:  built.Args(),
+			Label: found.Name,
+		})
+	}
+
+	return commands, missing
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"co2/database"
+	"co2/docker"
+	"co2/types"
+	"strings"
+	"testing"
+)
+
+func TestExecArgsTakesTheCommandAfterTheDash(t *testing.T) {
+	idents, command, _ := execArgs([]string{"a", "b", "echo", "Hello World"}, 2, "ignored")
+
+	if strings.Join(idents, ",") != "a,b" || strings.Join(command, "|") != "echo|Hello World" {
+		t.Error("execArgs should split at the dash, got", idents, command)
+	}
+}
+
+func TestExecArgsFallsBackToTheCommandFlag(t *testing.T) {
+	idents, command, _ := execArgs([]string{"a", "b"}, -1, "echo 'Hello World'")
+
+	if strings.Join(idents, ",") != "a,b" || strings.Join(command, "|") != "echo|Hello World" {
+		t.Error("execArgs should use the flag without a dash, got", idents, command)
+	}
+}
+
+func TestExecArgsRejectsUnbalancedQuotes(t *testing.T) {
+	_, _, err := execArgs([]string{"a"}, -1, "echo 'hi")
+
+	if err == nil {
+		t.Error("execArgs should fail on a command it can't split")
+	}
+}
+
+func TestGenerateExecCommandsFindsEveryContainer(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	database.AddContainer(types.Container{
+		Image:       "image1",
+		Name:        "service1-abc",
+		Uid:         "uid1",
+		ServiceName: "service1",
+		Host:        "tcp://remote:2375",
+	})
+
+	running := docker.RunningContainers()[0]
+
+	commands, missing := generateExecCommands([]string{running.Uid, "service1", "nope"}, []string{"echo", "hi"})
+
+	if len(commands) != 2 {
+		t.Fatal("generateExecCommands should find both containers, got", commands)
+	}
+
+	if commands[0].Label != running.Name || commands[0].Text != "docker exec "+running.Name+" echo hi" {
+		t.Error("generateExecCommands should find containers by uid, got", commands[0])
+	}
+
+	if commands[1].Label != "service1-abc" || commands[1].Text != "docker --host tcp://remote:2375 exec service1-abc echo hi" {
+		t.Error("generateExecCommands should find containers by service, got", commands[1])
+	}
+
+	if strings.Join(missing, ",") != "nope" {
+		t.Error("generateExecCommands should return what it couldn't find, got", missing)
+	}
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"co2/docker"
+	"co2/printer"
+	"co2/types"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+)
+
+var (
+	imagesCmd = &cobra.Command{
+		Use:   "images",
+		Short: "Shows the images the carbon services use and whether they're present",
+		Args:  cobra.NoArgs,
+		Run:   execImages,
+	}
+)
+
+// Lists every image that's referenced by a carbon.yml file,
+// along with the services that use it, and whether it's already
+// present locally, in which case its size and age are shown too.
+func execImages(cmd *cobra.Command, args []string) {
+	local, err := docker.Images()
+	if err != nil {
+		printer.Error("ERROR", "couldn't list the local images:", err.Error())
+		return
+	}
+
+	used := usedImages(fs.Services())
+	if len(used) == 0 {
+		printer.Info(printer.Cyan, "IMAGES", "No carbon service uses an image", "")
+		return
+	}
+
+	table := imagesTable(used, local)
+	table.Display()
+}
+
+// Groups the services by the image they use, with the
+// images written the same way docker lists them.
+//
+// Services that build their own image are grouped by the
+// tag they build it with.
+func usedImages(services types.CarbonConfig) map[string][]string {
+	used := map[string][]string{}
+
+	for name, service := range services {
+		if service.ImageTag() == "" {
+			continue
+		}
+
+		image := types.ImageReference(service.ImageTag())
+		used[image] = append(used[image], name)
+	}
+
+	return used
+}
+
+// Generates a table of the given images and the services that
+// use them, matched against the images that are present locally.
+func imagesTable(used map[string][]string, local []types.Image) printer.Table {
+	table := printer.NewTable(5)
+
+	table.Header(
+		"IMAGE",
+		"SERVICES",
+		"PRESENT",
+		"SIZE",
+		"AGE",
+	)
+
+	images := []string{}
+	for image := range used {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+
+	for _, image := range images {
+		services := used[image]
+		sort.Strings(services)
+
+		found, ok := localImage(image, local)
+		if !ok {
+			table.Row(image, strings.Join(services, ", "), staleStyle.Render("no"), "-", "-")
+			continue
+		}
+
+		table.Row(
+			image,
+			strings.Join(services, ", "),
+			"yes",
+			fadedStyle.Render(units.HumanSize(float64(found.Size))),
+			fadedStyle.Render(units.HumanDuration(time.Since(found.CreatedAt))+" ago"),
+		)
+	}
+
+	return table
+}
+
+// Finds the local image that's known by the given reference.
+func localImage(reference string, local []types.Image) (types.Image, bool) {
+	for _, image := range local {
+		if image.Is(reference) {
+			return image, true
+		}
+	}
+
+	return types.Image{}, false
+}
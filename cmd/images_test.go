@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"co2/types"
+	"strings"
+	"testing"
+)
+
+func TestImagesTableShowsWhichImagesArePresent(t *testing.T) {
+	used := usedImages(mockImageServices())
+	local := []types.Image{
+		{Tags: []string{"nginx:latest"}, Size: 1000},
+	}
+
+	table := imagesTable(used, local)
+	rows := table.Rows()[2:]
+
+	if len(rows) != 2 {
+		t.Fatal("imagesTable should have one row per image, got", len(rows))
+	}
+
+	if !strings.Contains(rows[0], "nginx:latest") || !strings.Contains(rows[0], "proxy, web") || !strings.Contains(rows[0], "yes") {
+		t.Error("imagesTable should show the present image with its services, got", rows[0])
+	}
+
+	if !strings.Contains(rows[1], "postgres:14") || !strings.Contains(rows[1], "no") {
+		t.Error("imagesTable should show the missing image, got", rows[1])
+	}
+}
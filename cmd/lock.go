@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"co2/helpers"
+	"co2/printer"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// Wraps the given command function so that it only ever
+// runs while holding the carbon lock.
+//
+// Use this for every command that changes any of the carbon
+// state (the database or the generated compose files), so that
+// two of them running at the same time don't step on each other.
+// Commands that only read never need this.
+//
+// If someone else is holding the lock, we wait for them to finish
+// until the lock timeout runs out and then give up entirely. If the
+// lock can't be taken at all, we give up right away.
+func locked(run func(*cobra.Command, []string)) func(*cobra.Command, []string) {
+	return func(cmd *cobra.Command, args []string) {
+		unlock, err := helpers.Lock(helpers.LockFile(), 0)
+
+		if err == helpers.ErrLocked {
+			printer.Extra(printer.Yellow, "Another co2 is running, waiting for it to finish")
+			unlock, err = helpers.Lock(helpers.LockFile(), lockTimeout)
+		}
+
+		if err != nil && err != helpers.ErrLocked {
+			printer.Error("ERROR", "couldn't take the carbon lock:", err.Error())
+			os.Exit(1)
+		}
+
+		if err != nil {
+			printer.Error("ERROR", "another co2 is running", "")
+			printer.Extra(
+				printer.Red,
+				"Gave up waiting for it after "+lockTimeout.String(),
+				"Try again once it's done, or wait longer with `--lock-timeout`",
+			)
+			os.Exit(1)
+		}
+
+		defer unlock()
+
+		run(cmd, args)
+	}
+}
@@ -8,17 +8,27 @@ import (
 	"co2/printer"
 	"co2/runner"
 	"co2/types"
+	"regexp"
 	"strings"
 
 	"github.com/spf13/cobra"
 )
 
 var (
-	follow bool
+	follow     bool
+	tail       string
+	since      string
+	until      string
+	timestamps bool
+	grep       string
+	exclude    string
+	merge      bool
+	archived   bool
+	runs       int
 
 	logsCmd = &cobra.Command{
 		Use:   "logs",
-		Short: "Shows the logs of the provided services",
+		Short: "Shows the logs of the provided services, or of their past runs",
 		Args:  cobra.MinimumNArgs(1),
 		Run:   execLogs,
 	}
@@ -27,6 +37,26 @@ var (
 // Set up all the required flags.
 func init() {
 	logsCmd.Flags().BoolVarP(&follow, "follow", "f", false, "follow the logs")
+	logsCmd.Flags().StringVarP(&tail, "tail", "n", "", "how many lines to show from the end of the logs, or `all`")
+	logsCmd.Flags().StringVar(&since, "since", "", "only show the logs since a timestamp, or a relative time like `10m`")
+	logsCmd.Flags().StringVar(&until, "until", "", "only show the logs until a timestamp, or a relative time like `10m`")
+	logsCmd.Flags().BoolVarP(&timestamps, "timestamps", "t", false, "show the time of every line")
+	logsCmd.Flags().StringVarP(&grep, "grep", "g", "", "only show the lines that match the given regex")
+	logsCmd.Flags().StringVarP(&exclude, "exclude", "x", "", "hide the lines that match the given regex")
+	logsCmd.Flags().BoolVarP(&merge, "merge", "m", false, "print the logs of all the containers in the order they happened in")
+	logsCmd.Flags().BoolVarP(&archived, "archived", "a", false, "show the logs carbon kept of the services when they were stopped")
+	logsCmd.Flags().IntVarP(&runs, "runs", "r", 1, "how many of the last runs to show the archived logs of")
+}
+
+// Everything that changes what the logs of each
+// container look like.
+type logsOptions struct {
+	Follow     bool
+	Tail       string
+	Since      string
+	Until      string
+	Timestamps bool
+	Filter     func(line string) bool
 }
 
 // Filters all the available containers based on the
@@ -36,15 +66,59 @@ func init() {
 // If none of the provided IDs or service names match any
 // of the existing containers, we don't do anything. Just inform
 // the user.
+//
+// The lines can be filtered with regexes as well, which happens
+// here rather than in docker, so it works the same way everywhere.
+//
+// When merging, the logs are read straight from docker instead, so
+// the lines of every container can be put in the order they happened.
+//
+// The logs of stopped services come from what carbon archived when
+// they were stopped, instead of from docker.
 func execLogs(cmd *cobra.Command, args []string) {
+	filter, err := lineFilter(grep, exclude)
+	if err != nil {
+		printer.Error("ERROR", "invalid regex:", err.Error())
+		return
+	}
+
+	if archived {
+		for _, service := range args {
+			showArchived(service, runs, filter)
+		}
+
+		return
+	}
+
+	options := logsOptions{
+		Follow:     follow,
+		Tail:       tail,
+		Since:      since,
+		Until:      until,
+		Timestamps: timestamps,
+		Filter:     filter,
+	}
+
 	matches := filterContainers(args)
-	commands := generateCommands(matches, follow)
+	commands := generateCommands(matches, options)
 
 	if !shouldRunLogsCommand(commands) {
 		printer.Error("ERROR", "no containers found", strings.Join(args, ", "))
 		return
 	}
 
+	if merge {
+		mergeLogs(matches, commands, docker.LogOptions{
+			Follow:     options.Follow,
+			Tail:       options.Tail,
+			Since:      options.Since,
+			Until:      options.Until,
+			Timestamps: options.Timestamps,
+		})
+
+		return
+	}
+
 	runner.Execute(commands...)
 }
 
@@ -59,26 +133,80 @@ func shouldRunLogsCommand(commands []types.Command) bool {
 //
 // This will build docker logs commands for each of the
 // containers it has been provided.
-func generateCommands(matches []types.Container, follow bool) []types.Command {
+func generateCommands(matches []types.Container, options logsOptions) []types.Command {
 	var commands = []types.Command{}
 
 	for _, match := range matches {
 		command := builder.DockerLogsCommand().
+			Host(match.Host).
 			Container(match.Name)
 
-		if follow {
+		if options.Follow {
 			command.Follow()
 		}
 
+		if options.Tail != "" {
+			command.Tail(options.Tail)
+		}
+
+		if options.Since != "" {
+			command.Since(options.Since)
+		}
+
+		if options.Until != "" {
+			command.Until(options.Until)
+		}
+
+		if options.Timestamps {
+			command.Timestamps()
+		}
+
 		commands = append(commands, types.Command{
-			Text:  command.Build(),
-			Label: match.Name,
+			Text:   command.Build(),
+			Args:   command.Args(),
+			Label:  match.Name,
+			Filter: options.Filter,
 		})
 	}
 
 	return commands
 }
 
+// Creates a filter that only lets through the lines that match
+// the first regex and don't match the second one. Either of them
+// can be empty, in which case it's not checked at all.
+//
+// If both are empty there's nothing to filter, so no filter
+// is returned either.
+func lineFilter(grep, exclude string) (func(line string) bool, error) {
+	if grep == "" && exclude == "" {
+		return nil, nil
+	}
+
+	var include, hide *regexp.Regexp
+	var err error
+
+	if grep != "" {
+		if include, err = regexp.Compile(grep); err != nil {
+			return nil, err
+		}
+	}
+
+	if exclude != "" {
+		if hide, err = regexp.Compile(exclude); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(line string) bool {
+		if include != nil && !include.MatchString(line) {
+			return false
+		}
+
+		return hide == nil || !hide.MatchString(line)
+	}, nil
+}
+
 // Filters the list of service names and IDs provided
 // by the user and tries to match them to a running container
 // instance.
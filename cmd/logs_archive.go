@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"bufio"
+	"co2/docker"
+	"co2/helpers"
+	"co2/printer"
+	"co2/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Keeps the logs of the given containers around in the carbon
+// home directory, one file for every time a service was started,
+// so they can still be looked at once the containers are gone.
+//
+// Anything that can't be archived is only pointed out, it
+// shouldn't ever keep a container from stopping.
+func archiveLogs(containers []types.Container) {
+	for _, container := range containers {
+		dir := filepath.Join(helpers.LogsDir(), container.ServiceName)
+
+		path, err := docker.ArchiveLogs(container.Host, container.Name, dir)
+		if err != nil {
+			printer.Extra(printer.Yellow, "Couldn't archive the logs of "+container.Name+": "+err.Error())
+			continue
+		}
+
+		if path != "" {
+			printer.Extra(printer.Green, "Archived the logs of "+container.Name+" to `"+path+"`")
+		}
+	}
+}
+
+// Finds the archived logs of the last few runs within the
+// given directory, oldest first.
+func archivedRuns(dir string, runs int) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".log") {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	// The names are the start times, so sorting them sorts the runs
+	sort.Strings(paths)
+
+	if runs > 0 && len(paths) > runs {
+		paths = paths[len(paths)-runs:]
+	}
+
+	return paths, nil
+}
+
+// Prints the archived logs of the last few runs of the given
+// service, each run under when it was started, with the lines
+// going through the given filter if there is one.
+func showArchived(service string, runs int, filter func(line string) bool) {
+	paths, err := archivedRuns(filepath.Join(helpers.LogsDir(), service), runs)
+	if err != nil || len(paths) == 0 {
+		printer.Error("ERROR", "no archived logs for:", service)
+		return
+	}
+
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".log")
+
+		started := name
+		if parsed, err := time.Parse(docker.ArchiveLayout, name); err == nil {
+			started = parsed.Local().Format(time.RFC1123)
+		}
+
+		printer.Info(printer.Cyan, "RUN", service+" started "+started, "")
+
+		if err := printArchived(path, filter); err != nil {
+			printer.Error("ERROR", "couldn't read "+path+":", err.Error())
+		}
+	}
+}
+
+// Prints every line of the given archive that
+// gets through the given filter.
+func printArchived(path string, filter func(line string) bool) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		if filter != nil && !filter(scanner.Text()) {
+			continue
+		}
+
+		printer.Ln(scanner.Text())
+	}
+
+	return scanner.Err()
+}
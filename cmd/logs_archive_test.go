@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"co2/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/4khara/replica"
+)
+
+func TestArchivedRunsReturnsTheLastRunsOldestFirst(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, name := range []string{
+		"2022-01-03T10-00-00Z.log",
+		"2022-01-01T10-00-00Z.log",
+		"2022-01-02T10-00-00Z.log",
+		"notes.txt",
+	} {
+		os.WriteFile(filepath.Join(dir, name), []byte(""), 0644)
+	}
+
+	paths, err := archivedRuns(dir, 2)
+	if err != nil {
+		t.Fatal("archivedRuns should read the directory, got", err)
+	}
+
+	names := []string{}
+	for _, path := range paths {
+		names = append(names, filepath.Base(path))
+	}
+
+	if strings.Join(names, ",") != "2022-01-02T10-00-00Z.log,2022-01-03T10-00-00Z.log" {
+		t.Error("archivedRuns should return the last two runs in order, got", names)
+	}
+}
+
+func TestArchivedRunsFailsWithoutArchives(t *testing.T) {
+	if _, err := archivedRuns(filepath.Join(t.TempDir(), "nope"), 1); err == nil {
+		t.Error("archivedRuns should fail when the service was never archived")
+	}
+}
+
+func TestPrintArchivedFiltersTheLines(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	path := filepath.Join(t.TempDir(), "2022-01-01T10-00-00Z.log")
+	os.WriteFile(path, []byte("ERROR one\nINFO two\nERROR three\n"), 0644)
+
+	filter, _ := lineFilter("ERROR", "")
+
+	if err := printArchived(path, filter); err != nil {
+		t.Fatal("printArchived should read the file, got", err)
+	}
+
+	if replica.Mocks.GetCallCount("Ln") != 2 {
+		t.Error("printArchived should only print the lines that match, got", replica.Mocks.GetCallCount("Ln"))
+	}
+
+	if replica.Mocks.GetCallParams("Ln")[1][0].(string) != "ERROR three" {
+		t.Error("printArchived should print the lines in order")
+	}
+}
+
+func TestStopContainersArchivesTheLogsOfStartedContainers(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	stopContainers(map[string][]types.Container{
+		"compose.yml": {
+			{Name: "never-started", ServiceName: "service1"},
+		},
+	})
+
+	if replica.Mocks.GetCallCount("Logs") != 0 {
+		t.Error("stopContainers shouldn't archive the logs of containers that never started")
+	}
+
+	if replica.Mocks.GetCallParams("Inspect")[0][0].(string) != "never-started" {
+		t.Error("stopContainers should look at every stopped container")
+	}
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"co2/docker"
+	"co2/printer"
+	"co2/runner"
+	"co2/types"
+	"fmt"
+	"time"
+)
+
+// How long a line waits for the lines of quieter containers
+// before it gets printed anyway, when following the logs.
+const mergeWindow = time.Second
+
+// A line that's waiting to be printed, along with
+// when it got to us.
+type pendingLine struct {
+	Line    types.LogLine
+	Arrived time.Time
+}
+
+// Something that happened to one of the streams being merged,
+// either a new line, or the stream closing.
+type mergeEvent struct {
+	Index  int
+	Line   types.LogLine
+	Closed bool
+}
+
+// Streams the logs of all the given containers at once, printed
+// as a single stream in the order docker received the lines in,
+// with the same labels the commands would print them with.
+//
+// The containers and commands belong together by index.
+func mergeLogs(matches []types.Container, commands []types.Command, options docker.LogOptions) {
+	inputs := make([]<-chan types.LogLine, len(matches))
+
+	for i, match := range matches {
+		lines := make(chan types.LogLine, 100)
+		inputs[i] = lines
+
+		go func(match types.Container, lines chan<- types.LogLine) {
+			defer close(lines)
+
+			if err := docker.StreamLogs(match.Host, match.Name, options, lines); err != nil {
+				printer.Error("ERROR", "couldn't read the logs of "+match.Name+":", err.Error())
+			}
+		}(match, lines)
+	}
+
+	window := time.Duration(0)
+	if options.Follow {
+		window = mergeWindow
+	}
+
+	mergeLines(inputs, window, func(index int, line types.LogLine) {
+		command := commands[index]
+
+		if command.Filter != nil && !command.Filter(line.Text) {
+			return
+		}
+
+		fmt.Println(runner.Label(command), line.Text)
+	})
+}
+
+// Merges the given streams of lines, each of them already in order,
+// into a single one ordered by time, and emits every line along with
+// the index of the stream it came from.
+//
+// A line is only emitted once every stream that's still open has a
+// line waiting, so nothing earlier can show up after it. When the
+// window isn't zero, lines that have waited that long are emitted
+// anyway, so a quiet stream doesn't hold back all the others.
+//
+// This returns once every stream has closed and been emitted.
+func mergeLines(inputs []<-chan types.LogLine, window time.Duration, emit func(int, types.LogLine)) {
+	events := make(chan mergeEvent)
+
+	for i, input := range inputs {
+		go func(i int, input <-chan types.LogLine) {
+			for line := range input {
+				events <- mergeEvent{Index: i, Line: line}
+			}
+
+			events <- mergeEvent{Index: i, Closed: true}
+		}(i, input)
+	}
+
+	var tick <-chan time.Time
+	if window > 0 {
+		ticker := time.NewTicker(window / 4)
+		defer ticker.Stop()
+
+		tick = ticker.C
+	}
+
+	pending := make([][]pendingLine, len(inputs))
+	closed := make([]bool, len(inputs))
+	open := len(inputs)
+
+	for open > 0 {
+		select {
+		case event := <-events:
+			if event.Closed {
+				closed[event.Index] = true
+				open--
+			} else {
+				pending[event.Index] = append(pending[event.Index], pendingLine{
+					Line:    event.Line,
+					Arrived: time.Now(),
+				})
+			}
+		case <-tick:
+		}
+
+		for {
+			earliest := -1
+			ready := true
+
+			for i := range pending {
+				if len(pending[i]) == 0 {
+					ready = ready && closed[i]
+					continue
+				}
+
+				if earliest < 0 || pending[i][0].Line.Time.Before(pending[earliest][0].Line.Time) {
+					earliest = i
+				}
+			}
+
+			if earliest < 0 {
+				break
+			}
+
+			stale := window > 0 && time.Since(pending[earliest][0].Arrived) >= window
+			if !ready && !stale {
+				break
+			}
+
+			emit(earliest, pending[earliest][0].Line)
+			pending[earliest] = pending[earliest][1:]
+		}
+	}
+}
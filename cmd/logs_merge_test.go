@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"co2/types"
+	"strings"
+	"testing"
+	"time"
+)
+
+func at(second int, text string) types.LogLine {
+	return types.LogLine{
+		Time: time.Date(2022, 1, 1, 10, 0, second, 0, time.UTC),
+		Text: text,
+	}
+}
+
+func closedInput(lines ...types.LogLine) <-chan types.LogLine {
+	input := make(chan types.LogLine, len(lines))
+	for _, line := range lines {
+		input <- line
+	}
+	close(input)
+
+	return input
+}
+
+func TestMergeLinesOrdersEverythingByTime(t *testing.T) {
+	inputs := []<-chan types.LogLine{
+		closedInput(at(1, "a1"), at(4, "a4"), at(5, "a5")),
+		closedInput(at(2, "b2"), at(3, "b3")),
+		closedInput(at(0, "c0"), at(6, "c6")),
+	}
+
+	merged := []string{}
+	mergeLines(inputs, 0, func(index int, line types.LogLine) {
+		merged = append(merged, line.Text)
+	})
+
+	expected := "c0 a1 b2 b3 a4 a5 c6"
+
+	if strings.Join(merged, " ") != expected {
+		t.Errorf("Expected %s, got %s", expected, strings.Join(merged, " "))
+	}
+}
+
+func TestMergeLinesEmitsTheIndexOfTheStream(t *testing.T) {
+	inputs := []<-chan types.LogLine{
+		closedInput(at(1, "a")),
+		closedInput(at(0, "b")),
+	}
+
+	indexes := []int{}
+	mergeLines(inputs, 0, func(index int, line types.LogLine) {
+		indexes = append(indexes, index)
+	})
+
+	if len(indexes) != 2 || indexes[0] != 1 || indexes[1] != 0 {
+		t.Error("Expected the index of the stream every line came from, got", indexes)
+	}
+}
+
+func TestMergeLinesDoesNotWaitForeverOnQuietStreams(t *testing.T) {
+	quiet := make(chan types.LogLine)
+	inputs := []<-chan types.LogLine{
+		closedInput(at(0, "a")),
+		quiet,
+	}
+
+	emitted := make(chan string, 1)
+	done := make(chan bool)
+
+	go func() {
+		mergeLines(inputs, 20*time.Millisecond, func(index int, line types.LogLine) {
+			emitted <- line.Text
+		})
+
+		done <- true
+	}()
+
+	select {
+	case text := <-emitted:
+		if text != "a" {
+			t.Error("Expected the waiting line to be emitted, got", text)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the line to be emitted once the window passed")
+	}
+
+	close(quiet)
+	<-done
+}
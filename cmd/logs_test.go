@@ -37,7 +37,7 @@ func TestGenerateCommandsReturnsSameAmountOfCommandsAsContainers(t *testing.T) {
 		},
 	}
 
-	commands := generateCommands(containers, false)
+	commands := generateCommands(containers, logsOptions{})
 
 	if len(commands) != len(containers) {
 		t.Error("generateCommands should return the same amount of commands as containers")
@@ -54,7 +54,7 @@ func TestCommandLabelMatchesContainerName(t *testing.T) {
 		},
 	}
 
-	commands := generateCommands(containers, true)
+	commands := generateCommands(containers, logsOptions{Follow: true})
 
 	if commands[0].Label != "container1" {
 		t.Error("generateCommands should set the label to the container name")
@@ -81,10 +81,12 @@ func TestContainersFilterFindsByCarbonServiceName(t *testing.T) {
 
 	containers := []types.Container{
 		{
+			Name:        "container1-name",
 			ServiceName: "container1",
 			Uid:         "uid1",
 		},
 		{
+			Name:        "container2-name",
 			ServiceName: "container2",
 			Uid:         "uid2",
 		},
@@ -107,10 +109,12 @@ func TestContainersMatchesBothUidAndServiceName(t *testing.T) {
 
 	containers := []types.Container{
 		{
+			Name:        "container1-name",
 			ServiceName: "container1",
 			Uid:         "uid1",
 		},
 		{
+			Name:        "container2-name",
 			ServiceName: "container2",
 			Uid:         "uid2",
 		},
@@ -132,3 +136,60 @@ func TestContainersMatchesBothUidAndServiceName(t *testing.T) {
 		t.Error("filterContainers should return 2 containers")
 	}
 }
+
+func TestGenerateCommandsPassesTheOptionsAlong(t *testing.T) {
+	containers := []types.Container{
+		{
+			Name: "container1",
+		},
+	}
+
+	commands := generateCommands(containers, logsOptions{
+		Tail:       "10",
+		Since:      "5m",
+		Timestamps: true,
+		Filter:     func(line string) bool { return true },
+	})
+
+	if commands[0].Text != "docker logs --tail 10 --since 5m -t container1" {
+		t.Error("generateCommands should add every option to the command, got", commands[0].Text)
+	}
+
+	if commands[0].Filter == nil {
+		t.Error("generateCommands should pass the filter along")
+	}
+}
+
+func TestLineFilterIsEmptyWithoutRegexes(t *testing.T) {
+	filter, err := lineFilter("", "")
+
+	if filter != nil || err != nil {
+		t.Error("lineFilter shouldn't filter anything without regexes")
+	}
+}
+
+func TestLineFilterMatchesAndExcludes(t *testing.T) {
+	filter, err := lineFilter("ERROR|WARN", "healthcheck")
+	if err != nil {
+		t.Fatal("lineFilter should accept valid regexes, got", err)
+	}
+
+	lines := map[string]bool{
+		"ERROR: something broke":      true,
+		"WARN: something might break": true,
+		"INFO: all good":              false,
+		"ERROR: healthcheck failed":   false,
+	}
+
+	for line, expected := range lines {
+		if filter(line) != expected {
+			t.Errorf("Expected %t for %q", expected, line)
+		}
+	}
+}
+
+func TestLineFilterRejectsInvalidRegexes(t *testing.T) {
+	if _, err := lineFilter("(", ""); err == nil {
+		t.Error("lineFilter should fail on an invalid regex")
+	}
+}
@@ -7,25 +7,37 @@ import (
 	"co2/printer"
 	"co2/runner"
 	"co2/types"
+	"fmt"
+	"io"
+	"os"
+	"strings"
 	"sync"
 
 	"github.com/4khara/replica"
 	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
 )
 
 type MockWrapperCmd struct{}
 
-func (w *MockWrapperCmd) RunningContainers() []dockerTypes.Container {
-	_, rv := replica.MockFn()
+func (w *MockWrapperCmd) RunningContainers(filters filters.Args) ([]dockerTypes.Container, error) {
+	_, rv := replica.MockFn(filters)
 
 	if rv != nil {
 		var containers []dockerTypes.Container
+		var err error
 
 		if rv[0] != nil {
 			containers = rv[0].([]dockerTypes.Container)
 		}
 
-		return containers
+		if len(rv) > 1 {
+			err = mockErr(rv[1])
+		}
+
+		return containers, err
 	}
 
 	return []dockerTypes.Container{
@@ -44,7 +56,244 @@ func (w *MockWrapperCmd) RunningContainers() []dockerTypes.Container {
 			Image: "image3",
 			Names: []string{"/docker-container3"},
 		},
+	}, nil
+}
+
+func (w *MockWrapperCmd) AllContainers(filters filters.Args) ([]dockerTypes.Container, error) {
+	_, rv := replica.MockFn(filters)
+
+	if rv != nil {
+		var containers []dockerTypes.Container
+
+		if rv[0] != nil {
+			containers = rv[0].([]dockerTypes.Container)
+		}
+
+		return containers, nil
+	}
+
+	return w.RunningContainers(filters)
+}
+
+func (w *MockWrapperCmd) Inspect(id string) (dockerTypes.ContainerJSON, error) {
+	_, rv := replica.MockFn(id)
+
+	if rv != nil {
+		return rv[0].(dockerTypes.ContainerJSON), mockErr(rv[1])
+	}
+
+	return dockerTypes.ContainerJSON{
+		ContainerJSONBase: &dockerTypes.ContainerJSONBase{
+			ID:    id,
+			State: &dockerTypes.ContainerState{Status: "running", Running: true},
+		},
+	}, nil
+}
+
+func (w *MockWrapperCmd) Remove(id string, options dockerTypes.ContainerRemoveOptions) error {
+	_, rv := replica.MockFn(id, options)
+
+	if rv != nil {
+		return mockErr(rv[0])
+	}
+
+	return nil
+}
+
+func (w *MockWrapperCmd) Logs(id string, options dockerTypes.ContainerLogsOptions) (io.ReadCloser, error) {
+	_, rv := replica.MockFn(id, options)
+
+	if rv != nil {
+		var stream io.ReadCloser
+
+		if rv[0] != nil {
+			stream = rv[0].(io.ReadCloser)
+		}
+
+		return stream, mockErr(rv[1])
+	}
+
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
+func (w *MockWrapperCmd) Events(filters filters.Args) (<-chan events.Message, <-chan error) {
+	_, rv := replica.MockFn(filters)
+
+	messages := make(chan events.Message)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(messages)
+
+		if rv != nil {
+			for _, message := range rv[0].([]events.Message) {
+				messages <- message
+			}
+		}
+	}()
+
+	return messages, errs
+}
+
+func (w *MockWrapperCmd) Stats(id string) (dockerTypes.StatsJSON, error) {
+	_, rv := replica.MockFn(id)
+
+	if rv != nil {
+		return rv[0].(dockerTypes.StatsJSON), mockErr(rv[1])
+	}
+
+	return dockerTypes.StatsJSON{}, nil
+}
+
+func (w *MockWrapperCmd) Pull(image string) (io.ReadCloser, error) {
+	_, rv := replica.MockFn(image)
+
+	if rv != nil {
+		var stream io.ReadCloser
+
+		if rv[0] != nil {
+			stream = rv[0].(io.ReadCloser)
+		}
+
+		return stream, mockErr(rv[1])
+	}
+
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
+func (w *MockWrapperCmd) Images(filters filters.Args) ([]dockerTypes.ImageSummary, error) {
+	_, rv := replica.MockFn(filters)
+
+	if rv != nil {
+		var images []dockerTypes.ImageSummary
+
+		if rv[0] != nil {
+			images = rv[0].([]dockerTypes.ImageSummary)
+		}
+
+		return images, mockErr(rv[1])
+	}
+
+	return []dockerTypes.ImageSummary{}, nil
+}
+
+func (w *MockWrapperCmd) StatPath(id string, path string) (dockerTypes.ContainerPathStat, error) {
+	_, rv := replica.MockFn(id, path)
+
+	if rv != nil {
+		return rv[0].(dockerTypes.ContainerPathStat), mockErr(rv[1])
+	}
+
+	return dockerTypes.ContainerPathStat{Name: path, Mode: os.ModeDir}, nil
+}
+
+func (w *MockWrapperCmd) CopyFrom(id string, path string) (io.ReadCloser, dockerTypes.ContainerPathStat, error) {
+	_, rv := replica.MockFn(id, path)
+
+	if rv != nil {
+		var stream io.ReadCloser
+
+		if rv[0] != nil {
+			stream = rv[0].(io.ReadCloser)
+		}
+
+		return stream, rv[1].(dockerTypes.ContainerPathStat), mockErr(rv[2])
+	}
+
+	return io.NopCloser(strings.NewReader("")), dockerTypes.ContainerPathStat{}, nil
+}
+
+// The archive is read right away so that whatever is writing
+// it can finish, and so that tests can look at what was sent.
+func (w *MockWrapperCmd) CopyTo(id string, path string, content io.Reader) error {
+	archive, _ := io.ReadAll(content)
+	_, rv := replica.MockFn(id, path, archive)
+
+	if rv != nil {
+		return mockErr(rv[0])
+	}
+
+	return nil
+}
+
+func (w *MockWrapperCmd) NetworkInspect(name string) (dockerTypes.NetworkResource, error) {
+	_, rv := replica.MockFn(name)
+
+	if rv != nil {
+		return rv[0].(dockerTypes.NetworkResource), mockErr(rv[1])
+	}
+
+	return dockerTypes.NetworkResource{Name: name, Driver: "bridge"}, nil
+}
+
+func (w *MockWrapperCmd) NetworkCreate(name string, options dockerTypes.NetworkCreate) error {
+	_, rv := replica.MockFn(name, options)
+
+	if rv != nil {
+		return mockErr(rv[0])
+	}
+
+	return nil
+}
+
+func (w *MockWrapperCmd) DiskUsage() (dockerTypes.DiskUsage, error) {
+	_, rv := replica.MockFn()
+
+	if rv != nil {
+		return rv[0].(dockerTypes.DiskUsage), mockErr(rv[1])
+	}
+
+	return dockerTypes.DiskUsage{}, nil
+}
+
+func (w *MockWrapperCmd) VolumeRemove(name string, force bool) error {
+	_, rv := replica.MockFn(name, force)
+
+	if rv != nil {
+		return mockErr(rv[0])
 	}
+
+	return nil
+}
+
+func (w *MockWrapperCmd) Create(config *container.Config, host *container.HostConfig) (string, error) {
+	count, rv := replica.MockFn(config, host)
+
+	if rv != nil {
+		return rv[0].(string), mockErr(rv[1])
+	}
+
+	return fmt.Sprintf("created-%d", count), nil
+}
+
+func (w *MockWrapperCmd) Start(id string) error {
+	_, rv := replica.MockFn(id)
+
+	if rv != nil {
+		return mockErr(rv[0])
+	}
+
+	return nil
+}
+
+func (w *MockWrapperCmd) Wait(id string) (int64, error) {
+	_, rv := replica.MockFn(id)
+
+	if rv != nil {
+		return rv[0].(int64), mockErr(rv[1])
+	}
+
+	return 0, nil
+}
+
+// Converts a mocked return value into an error,
+// since nil can't be asserted into one.
+func mockErr(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	return value.(error)
 }
 
 type MockFs struct{}
@@ -67,10 +316,16 @@ func (f MockFs) Services() types.CarbonConfig {
 
 type MockExecutor struct{}
 
-func (e *MockExecutor) Execute(done *sync.WaitGroup, command string, label string) {
-	replica.MockFn(done, command, label)
+func (e *MockExecutor) Execute(done *sync.WaitGroup, command types.Command, label string) int {
+	_, rv := replica.MockFn(done, command, label)
 
 	done.Done()
+
+	if rv != nil {
+		return rv[0].(int)
+	}
+
+	return 0
 }
 
 type MockPrinter struct{}
@@ -85,6 +340,7 @@ func beforeCmdTest() {
 	printer.WrapStdout(&MockPrinter{})
 	runner.CustomExecutor(&MockExecutor{})
 	docker.CustomWrapper(&MockWrapperCmd{})
+	docker.UseHost("")
 
 	replica.Mocks.Clear()
 
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"co2/docker"
+	"co2/printer"
+	"co2/types"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	networkCmd = &cobra.Command{
+		Use:   "network",
+		Short: "Shows which carbon containers share which docker networks",
+		Args:  cobra.NoArgs,
+		Run:   execNetwork,
+	}
+)
+
+// A single container within a network, along with how
+// it can be reached from within that network.
+type networkMember struct {
+	Container types.Container
+	Network   types.ContainerNetwork
+}
+
+// Shows every network the running carbon containers are attached
+// to, with the containers in each of them and how they can be
+// reached there.
+//
+// Services started together or apart all share one network, but
+// containers started before it existed don't, so services that
+// depend on each other can still end up unable to reach each other.
+// Those get pointed out.
+func execNetwork(cmd *cobra.Command, args []string) {
+	containers := docker.RunningContainers(types.LabelService)
+	if len(containers) == 0 {
+		printer.Info(printer.Cyan, "NETWORK", "No running carbon containers", "")
+		return
+	}
+
+	attached := map[string][]types.ContainerNetwork{}
+	for _, container := range containers {
+		networks, err := docker.Networks(container.Name)
+		if err != nil {
+			continue
+		}
+
+		attached[container.Name] = networks
+	}
+
+	names, members := byNetwork(containers, attached)
+	for _, name := range names {
+		printer.Info(printer.Cyan, "NETWORK", name, "")
+
+		table := networkTable(members[name])
+		table.Display()
+	}
+
+	if warnings := disjoint(containers, attached, fs.Services(), docker.SharedNetwork()); len(warnings) > 0 {
+		printer.Extra(printer.Yellow, warnings...)
+	}
+}
+
+// Groups the given containers by the networks they're attached
+// to. The names of the networks are returned sorted as well, so they
+// always show up in the same order.
+func byNetwork(containers []types.Container, attached map[string][]types.ContainerNetwork) ([]string, map[string][]networkMember) {
+	members := map[string][]networkMember{}
+	names := []string{}
+
+	for _, container := range containers {
+		for _, network := range attached[container.Name] {
+			if _, ok := members[network.Name]; !ok {
+				names = append(names, network.Name)
+			}
+
+			members[network.Name] = append(members[network.Name], networkMember{
+				Container: container,
+				Network:   network,
+			})
+		}
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		group := members[name]
+
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].Container.Name < group[j].Container.Name
+		})
+	}
+
+	return names, members
+}
+
+// Generates a table of the containers within a single network.
+func networkTable(members []networkMember) printer.Table {
+	table := printer.NewTable(4)
+
+	table.Header(
+		"SERVICE",
+		"NAME",
+		"ALIASES",
+		"IP",
+	)
+
+	for _, member := range members {
+		aliases := strings.Join(member.Network.Aliases, ", ")
+		if aliases == "" {
+			aliases = "-"
+		}
+
+		table.Row(
+			member.Container.Labels[types.LabelService],
+			fadedStyle.Render(member.Container.Name),
+			aliases,
+			fadedStyle.Render(member.Network.IP),
+		)
+	}
+
+	return table
+}
+
+// Finds the running services that depend on other running
+// services without sharing a single network with them, meaning
+// they have no way of reaching each other by name.
+//
+// Only services that aren't attached to the shared network
+// can end up like that, the others are never checked.
+func disjoint(containers []types.Container, attached map[string][]types.ContainerNetwork, services types.CarbonConfig, shared string) []string {
+	networks := map[string]map[string]bool{}
+
+	for _, container := range containers {
+		service := container.Labels[types.LabelService]
+		if _, ok := networks[service]; !ok {
+			networks[service] = map[string]bool{}
+		}
+
+		for _, network := range attached[container.Name] {
+			networks[service][network.Name] = true
+		}
+	}
+
+	warnings := []string{}
+
+	for service, own := range networks {
+		for _, dep := range services[service].DependsOn {
+			theirs, ok := networks[dep]
+			if !ok || (own[shared] && theirs[shared]) {
+				continue
+			}
+
+			shared := false
+			for network := range own {
+				if theirs[network] {
+					shared = true
+					break
+				}
+			}
+
+			if !shared {
+				warnings = append(warnings, fmt.Sprintf("'%s' depends on '%s' but they don't share a network", service, dep))
+			}
+		}
+	}
+
+	sort.Strings(warnings)
+	return warnings
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"co2/types"
+	"strings"
+	"testing"
+)
+
+func mockNetworkedContainers() ([]types.Container, map[string][]types.ContainerNetwork) {
+	containers := []types.Container{
+		{Name: "api-1", Labels: map[string]string{types.LabelService: "api"}},
+		{Name: "db-1", Labels: map[string]string{types.LabelService: "db"}},
+		{Name: "web-1", Labels: map[string]string{types.LabelService: "web"}},
+	}
+
+	attached := map[string][]types.ContainerNetwork{
+		"api-1": {{Name: "a_default", IP: "172.20.0.2", Aliases: []string{"api"}}},
+		"db-1":  {{Name: "b_default", IP: "172.21.0.2", Aliases: []string{"db"}}},
+		"web-1": {{Name: "a_default", IP: "172.20.0.3"}, {Name: "bridge", IP: "172.17.0.2"}},
+	}
+
+	return containers, attached
+}
+
+func TestByNetworkGroupsTheContainers(t *testing.T) {
+	names, members := byNetwork(mockNetworkedContainers())
+
+	if strings.Join(names, ",") != "a_default,b_default,bridge" {
+		t.Fatal("byNetwork should return the sorted networks, got", names)
+	}
+
+	shared := members["a_default"]
+	if len(shared) != 2 || shared[0].Container.Name != "api-1" || shared[1].Container.Name != "web-1" {
+		t.Error("byNetwork should group the containers of each network, got", shared)
+	}
+}
+
+func TestNetworkTableShowsAliasesAndAddresses(t *testing.T) {
+	_, members := byNetwork(mockNetworkedContainers())
+
+	table := networkTable(members["a_default"])
+	rows := table.Rows()[2:]
+
+	if len(rows) != 2 {
+		t.Fatal("networkTable should have one row per container, got", len(rows))
+	}
+
+	if !strings.Contains(rows[0], "api") || !strings.Contains(rows[0], "172.20.0.2") {
+		t.Error("networkTable should show the alias and address, got", rows[0])
+	}
+
+	if !strings.Contains(rows[1], "-") {
+		t.Error("networkTable should show a dash without aliases, got", rows[1])
+	}
+}
+
+func TestDisjointWarnsAboutDependenciesWithoutASharedNetwork(t *testing.T) {
+	containers, attached := mockNetworkedContainers()
+	services := types.CarbonConfig{
+		"api": {Name: "api", DependsOn: []string{"db", "missing"}},
+		"web": {Name: "web", DependsOn: []string{"api"}},
+	}
+
+	warnings := disjoint(containers, attached, services, "carbon")
+
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "'api' depends on 'db'") {
+		t.Error("disjoint should only warn about api and db, got", warnings)
+	}
+}
+
+func TestDisjointLeavesTheSharedNetworkAlone(t *testing.T) {
+	containers, attached := mockNetworkedContainers()
+	attached["api-1"] = append(attached["api-1"], types.ContainerNetwork{Name: "carbon"})
+	attached["db-1"] = append(attached["db-1"], types.ContainerNetwork{Name: "carbon"})
+
+	services := types.CarbonConfig{
+		"api": {Name: "api", DependsOn: []string{"db"}},
+	}
+
+	if warnings := disjoint(containers, attached, services, "carbon"); len(warnings) != 0 {
+		t.Error("disjoint shouldn't warn about services on the shared network, got", warnings)
+	}
+}
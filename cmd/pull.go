@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"co2/docker"
+	"co2/helpers"
+	"co2/printer"
+	"co2/runner"
+	"co2/types"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	pullCmd = &cobra.Command{
+		Use:   "pull <services|@all>",
+		Short: "Pulls the images of the provided services",
+		Args:  cobra.MinimumNArgs(1),
+		Run:   execPull,
+	}
+)
+
+// Pulls the images of all the provided services at once,
+// or of every known service if `@all` is provided.
+//
+// Every image gets its own label, the same way the output of
+// running commands does, so the progress of each one can be
+// told apart. If any of the images can't be pulled, this exits
+// with an error once all the others are done.
+func execPull(cmd *cobra.Command, args []string) {
+	images, chosen := pullable(args)
+	if len(images) == 0 {
+		printer.Info(printer.Cyan, "PULL", "Nothing to pull", "")
+		return
+	}
+
+	host, err := startHost(chosen)
+	if err != nil {
+		printer.Error("ERROR", err.Error(), "")
+		printer.Extra(printer.Red, "Use `--host` or `--context` to pick one", "Aborting")
+		return
+	}
+	docker.UseHost(host)
+
+	names := []string{}
+	for image := range images {
+		names = append(names, image)
+	}
+	sort.Strings(names)
+
+	printer.Info(printer.Green, "PULL", "Pulling images:", strings.Join(names, ", "))
+
+	if failed := pull(names); len(failed) > 0 {
+		printer.Error("ERROR", "couldn't pull some of the images:", strings.Join(failed, ", "))
+		printer.Extra(printer.Grey, "Images from private registries need a `docker login` to them first")
+		os.Exit(1)
+	}
+}
+
+// Works out which images need to be pulled for the given
+// services, along with the services they belong to.
+//
+// Services that don't exist, or that don't have an image to
+// pull, are pointed out and left out.
+func pullable(args []string) (map[string][]string, types.CarbonConfig) {
+	configs := fs.Services()
+	chosen := types.CarbonConfig{}
+
+	if helpers.Contains(args, "@all") {
+		chosen = configs
+	} else {
+		for _, service := range args {
+			found, ok := configs[service]
+			if !ok {
+				printer.Extra(printer.Red, "No carbon file found for: "+service)
+				continue
+			}
+
+			chosen[service] = found
+		}
+	}
+
+	images := map[string][]string{}
+
+	for name, service := range chosen {
+		if service.Image == "" {
+			printer.Extra(printer.Grey, "No image to pull for: "+name)
+			delete(chosen, name)
+			continue
+		}
+
+		image := types.ImageReference(service.Image)
+		images[image] = append(images[image], name)
+	}
+
+	return images, chosen
+}
+
+// Pulls all the given images in parallel, showing their progress
+// as it comes, and returns the ones that failed.
+func pull(images []string) []string {
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	failed := []string{}
+
+	for _, image := range images {
+		wg.Add(1)
+
+		go func(image string) {
+			defer wg.Done()
+
+			label := runner.Label(types.Command{Text: image, Label: image})
+
+			err := docker.Pull(image, func(line string) {
+				mutex.Lock()
+				printer.Ln(label + line)
+				mutex.Unlock()
+			})
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if err != nil {
+				printer.Ln(label + printer.Render(printer.Red, "ERROR", err.Error(), ""))
+				failed = append(failed, image)
+			}
+		}(image)
+	}
+
+	wg.Wait()
+	sort.Strings(failed)
+
+	return failed
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"co2/types"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/4khara/replica"
+)
+
+func mockImageServices() types.CarbonConfig {
+	return types.CarbonConfig{
+		"web":    {Name: "web", Image: "nginx"},
+		"proxy":  {Name: "proxy", Image: "docker.io/library/nginx:latest"},
+		"db":     {Name: "db", Image: "postgres:14"},
+		"worker": {Name: "worker"},
+	}
+}
+
+func TestPullableGroupsServicesByImage(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	replica.Mocks.SetReturnValues("Services", mockImageServices())
+
+	images, chosen := pullable([]string{"@all"})
+
+	if len(images) != 2 {
+		t.Fatal("pullable should return every image once, got", images)
+	}
+
+	if len(images["nginx:latest"]) != 2 || len(images["postgres:14"]) != 1 {
+		t.Error("pullable should group the services by image, got", images)
+	}
+
+	if _, ok := chosen["worker"]; ok {
+		t.Error("pullable should leave out services without an image")
+	}
+}
+
+func TestPullableOnlyTakesTheProvidedServices(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	replica.Mocks.SetReturnValues("Services", mockImageServices())
+
+	images, _ := pullable([]string{"db", "missing"})
+
+	if len(images) != 1 || len(images["postgres:14"]) != 1 {
+		t.Error("pullable should only return the images of the provided services, got", images)
+	}
+}
+
+func TestPullReturnsTheImagesThatFailed(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	replica.Mocks.SetReturnValues("Pull", nil, errors.New("manifest unknown"))
+
+	failed := pull([]string{"postgres:14", "nginx:latest"})
+
+	if strings.Join(failed, ",") != "nginx:latest,postgres:14" {
+		t.Error("pull should return every image that failed, got", failed)
+	}
+
+	if replica.Mocks.GetCallCount("Pull") != 2 {
+		t.Error("pull should pull every image, got", replica.Mocks.GetCallCount("Pull"))
+	}
+}
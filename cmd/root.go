@@ -1,14 +1,25 @@
 package cmd
 
 import (
+	"co2/docker"
+	"co2/printer"
+	"os"
+	"time"
+
 	"github.com/spf13/cobra"
 )
 
 var (
+	lockTimeout   time.Duration
+	dockerHost    string
+	dockerContext string
+	networkName   string
+
 	rootCmd = &cobra.Command{
-		Use:   "carbon",
-		Short: "Mess around with containers!!",
-		Long:  "Flip, Twist, and turn all your containers!!!",
+		Use:              "carbon",
+		Short:            "Mess around with containers!!",
+		Long:             "Flip, Twist, and turn all your containers!!!",
+		PersistentPreRun: useDockerHost,
 	}
 )
 
@@ -20,6 +31,11 @@ func Execute() error {
 
 // Registers all subcommands
 func init() {
+	rootCmd.PersistentFlags().DurationVar(&lockTimeout, "lock-timeout", 10*time.Second, "How long to wait for another running co2 to finish")
+	rootCmd.PersistentFlags().StringVarP(&dockerHost, "host", "H", "", "The docker daemon to talk to, instead of the one from the environment")
+	rootCmd.PersistentFlags().StringVar(&dockerContext, "context", "", "The docker context to use, instead of the one from the environment")
+	rootCmd.PersistentFlags().StringVar(&networkName, "network", "", "The network every carbon service joins, instead of $CO2_NETWORK or `carbon`")
+
 	rootCmd.AddCommand(startCmd)
 	rootCmd.AddCommand(stopCmd)
 
@@ -27,4 +43,39 @@ func init() {
 	rootCmd.AddCommand(storeCmd)
 	rootCmd.AddCommand(shellCmd)
 	rootCmd.AddCommand(logsCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(watchCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(pullCmd)
+	rootCmd.AddCommand(imagesCmd)
+	rootCmd.AddCommand(buildCmd)
+	rootCmd.AddCommand(execCmd)
+	rootCmd.AddCommand(cpCmd)
+	rootCmd.AddCommand(networkCmd)
+	rootCmd.AddCommand(volumesCmd)
+}
+
+// Points everything that talks to docker at the daemon and the
+// network the user asked for, if they asked for them at all.
+//
+// Contexts are resolved to their host right away so that the
+// api and the docker cli always end up talking to the same daemon.
+func useDockerHost(cmd *cobra.Command, args []string) {
+	if dockerContext != "" {
+		if dockerHost != "" {
+			printer.Error("ERROR", "`--host` and `--context` can't be used together", "")
+			os.Exit(1)
+		}
+
+		host, err := docker.ContextHost(dockerContext)
+		if err != nil {
+			printer.Error("ERROR", "couldn't use the docker context:", err.Error())
+			os.Exit(1)
+		}
+
+		dockerHost = host
+	}
+
+	docker.UseHost(dockerHost)
+	docker.UseNetwork(networkName)
 }
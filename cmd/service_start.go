@@ -3,25 +3,36 @@ package cmd
 import (
 	"co2/builder"
 	"co2/database"
+	"co2/docker"
 	"co2/helpers"
 	"co2/printer"
 	"co2/runner"
 	"co2/types"
 	"errors"
 	"fmt"
+	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
 
 var (
-	force bool
+	force       bool
+	buildFirst  bool
+	waitReady   bool
+	waitTimeout time.Duration
+
+	// How long to wait between checking whether
+	// the started services are ready yet.
+	waitInterval = 500 * time.Millisecond
 
 	startCmd = &cobra.Command{
 		Use:   "start",
 		Short: "Starts the provided services",
 		Args:  cobra.MinimumNArgs(1),
-		Run:   start,
+		Run:   locked(start),
 	}
 )
 
@@ -29,6 +40,9 @@ var (
 func init() {
 	help := "Force the start of the service. This will delete the old ones before starting."
 	startCmd.Flags().BoolVarP(&force, "force", "f", false, help)
+	startCmd.Flags().BoolVarP(&buildFirst, "build", "b", false, "build the images of the services that have a build section before starting")
+	startCmd.Flags().BoolVarP(&waitReady, "wait", "w", false, "wait until all the services are healthy, or running if they have no health check")
+	startCmd.Flags().DurationVar(&waitTimeout, "timeout", time.Minute, "how long to wait for the services when using `--wait`")
 }
 
 // Starts the service start command.
@@ -39,6 +53,13 @@ func init() {
 // the stop command beforehand so that all the services we're
 // trying to start will start fresh.
 //
+// If we've been asked to build, the images of all the services that
+// build their own get built before anything starts.
+//
+// If we've been asked to wait, this only returns once all the
+// services are ready, and exits with an error if any of them
+// never get there.
+//
 // We also want to make sure that we tell the docker compose command
 // to run with any of the available environment files that might be
 // provided by the current store we are looking at.
@@ -65,13 +86,53 @@ func start(cmd *cobra.Command, args []string) {
 	}
 
 	extracted := extract(args)
-	envs, composeFile, err := compose(extracted)
+
+	host, err := startHost(extracted)
+	if err != nil {
+		printer.Error("ERROR", err.Error(), "")
+		printer.Extra(printer.Red, "Use `--host` or `--context` to pick one", "Aborting")
+		return
+	}
+	docker.UseHost(host)
+
+	if conflicts := portConflicts(extracted); len(conflicts) > 0 {
+		printer.Error("ERROR", "ports already in use by other carbon services", "")
+		printer.Extra(printer.Red, conflicts...)
+		printer.Extra(printer.Grey, "Aborting")
+		return
+	}
+
+	if building := builds(extracted); buildFirst && len(building) > 0 {
+		if failed := build(building); len(failed) > 0 {
+			printer.Error("ERROR", "couldn't build:", strings.Join(failed, ", "))
+			printer.Extra(printer.Grey, "Aborting")
+			return
+		}
+	}
+
+	if err := docker.EnsureNetwork(docker.SharedNetwork()); err != nil {
+		printer.Error("ERROR", "couldn't create the shared network:", err.Error())
+		printer.Extra(printer.Grey, "Aborting")
+		return
+	}
+
+	envs, composeFile, err := compose(extracted, args)
 	if err != nil {
 		printer.Extra(printer.Grey, "Aborting")
 		return
 	}
-	containerize(composeFile)
+	containerize(composeFile, extracted, args)
 	run(composeFile, envs, args)
+	inspectStarted(composeFile)
+
+	if !waitReady {
+		return
+	}
+
+	if failed := waitFor(composeFile, waitTimeout); len(failed) > 0 {
+		printer.Error("ERROR", "services never became ready:", strings.Join(failed, ", "))
+		os.Exit(1)
+	}
 }
 
 // Generates and runs the docker compose command based on the
@@ -79,8 +140,9 @@ func start(cmd *cobra.Command, args []string) {
 // that the user has provided.
 func run(file types.ComposeFile, envs []string, services []string) {
 	command := builder.DockerComposeCommand().
+		Host(docker.DefaultHost()).
 		File(file.Path()).
-		Service(strings.Join(services, " ")).
+		Service(services...).
 		Background().
 		Up()
 
@@ -92,6 +154,7 @@ func run(file types.ComposeFile, envs []string, services []string) {
 	printer.Extra(printer.Green, "Executing `docker compose` command on the new file\n")
 	runner.Execute(types.Command{
 		Text: command.Build(),
+		Args: command.Args(),
 	})
 }
 
@@ -179,7 +242,21 @@ func extract(args []string) types.CarbonConfig {
 // services, if they exist. This will make sure to inject all of
 // the required values into all the containers within the compose
 // file.
-func compose(choices types.CarbonConfig) ([]string, types.ComposeFile, error) {
+//
+// Each service only gets the environment files of the store it
+// belongs to, along with the ones it defines itself. The returned
+// environment files are the ones of all the stores together, they're
+// only meant for the variable substitution within the compose file.
+//
+// Every service also gets labelled with everything carbon knows
+// about it, so the containers can be traced back to their services
+// straight from docker. That includes the arguments the user started
+// them with, the same ones that end up in the database.
+//
+// All of them join the network carbon shares between every service
+// it starts, where they can be reached by their service name, so
+// services started separately can still talk to each other.
+func compose(choices types.CarbonConfig, args []string) ([]string, types.ComposeFile, error) {
 	envs := []string{}
 	if len(choices) == 0 {
 		return envs, types.ComposeFile{}, errors.New("no services found")
@@ -187,46 +264,193 @@ func compose(choices types.CarbonConfig) ([]string, types.ComposeFile, error) {
 
 	printer.Extra(printer.Green, "Generating compose file")
 	compose := types.NewComposeFile()
+	network := docker.SharedNetwork()
 
 	// Add all the services to the compose file
 	for _, service := range choices {
+		if files := service.EnvFiles(); len(files) > 0 {
+			service.FullContents["env_file"] = files
+		}
+
+		if service.Builds() {
+			service.FullContents["image"] = service.ImageTag()
+			service.FullContents["build"] = buildSection(service)
+		}
+
+		// Services that share the network of something else can't join any
+		if _, ok := service.FullContents["network_mode"]; !ok {
+			service.FullContents["networks"] = types.JoinNetwork(service.FullContents["networks"], network, service.Name)
+			compose.Networks[network] = types.ComposeNetwork{External: true}
+		}
+
 		compose.Services[service.Name] = service.FullContents
 	}
 
+	// The path depends on all the services so it's only
+	// known once they've all been added.
+	startedAt := time.Now().Format(time.RFC3339)
+
+	for _, service := range choices {
+		labels := map[string]string{
+			types.LabelService:     service.Name,
+			types.LabelCarbonFile:  service.Path,
+			types.LabelComposeFile: compose.Path(),
+			types.LabelStartedAt:   startedAt,
+			types.LabelStartArgs:   strings.Join(args, " "),
+		}
+
+		if service.Store != nil {
+			labels[types.LabelStore] = service.Store.Uid
+		}
+
+		service.FullContents["labels"] = types.MergeLabels(service.FullContents["labels"], labels)
+	}
+
 	printer.Extra(printer.Green, "Saving compose file to `"+compose.Path()+"`")
 	compose.Save()
 
 	// Find all the env files that should be given to the compose file
 	for _, service := range choices {
-		if service.Store.Env == "" {
+		if service.Store == nil {
 			continue
 		}
 
-		if !helpers.Contains(envs, service.Store.Env) {
-			envs = append(envs, service.Store.Env)
+		for _, env := range service.Store.Envs {
+			if !helpers.Contains(envs, env) {
+				envs = append(envs, env)
+			}
 		}
 	}
 
 	return envs, compose, nil
 }
 
+// Rewrites the build section of the given service for the
+// generated compose file, which lives somewhere else entirely, so
+// the context has to point to where the carbon.yml says it is.
+//
+// The image is always set next to it, which makes compose tag what
+// it builds with it, and use the one we built if it's already there.
+func buildSection(service types.CarbonService) map[string]interface{} {
+	section := map[string]interface{}{
+		"context": service.BuildContext(),
+	}
+
+	if service.Build.Dockerfile != "" {
+		section["dockerfile"] = service.Build.Dockerfile
+	}
+
+	if len(service.Build.Args) > 0 {
+		section["args"] = service.Build.Args
+	}
+
+	return section
+}
+
+// Works out which docker host the given services should be
+// started on.
+//
+// A host that was asked for explicitly always wins. Otherwise it's
+// the host of the stores the services come from, which all need to
+// agree since the services are started with a single compose file.
+func startHost(choices types.CarbonConfig) (string, error) {
+	if host := docker.DefaultHost(); host != "" {
+		return host, nil
+	}
+
+	hosts := []string{}
+
+	for _, service := range choices {
+		host := ""
+		if service.Store != nil {
+			host = service.Store.Host
+		}
+
+		if !helpers.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+
+	if len(hosts) > 1 {
+		named := []string{}
+		for _, host := range hosts {
+			if host == "" {
+				host = "default"
+			}
+
+			named = append(named, host)
+		}
+		sort.Strings(named)
+
+		return "", fmt.Errorf("the services belong to different docker hosts: %s", strings.Join(named, ", "))
+	}
+
+	if len(hosts) == 0 {
+		return "", nil
+	}
+
+	return hosts[0], nil
+}
+
+// Checks whether or not any of the ports that the provided
+// services want to publish are already published by another
+// carbon container on the same docker host, and describes every
+// conflict it finds.
+func portConflicts(choices types.CarbonConfig) []string {
+	conflicts := []string{}
+
+	for name, service := range choices {
+		for _, port := range types.ParsePorts(service.FullContents["ports"]) {
+			for _, container := range database.ContainersOnPort(port) {
+				if container.Host != docker.DefaultHost() {
+					continue
+				}
+
+				conflicts = append(conflicts, fmt.Sprintf(
+					"'%s' wants to publish %d/%s which is already used by '%s'",
+					name,
+					port.HostPort,
+					port.Protocol,
+					container.ServiceName,
+				))
+			}
+		}
+	}
+
+	sort.Strings(conflicts)
+	return conflicts
+}
+
 // Creates container types for each of the provided services
 // within the compose file, making sure that all the containers
-// know which file they belong to.
+// know which file, and which store, they belong to.
 //
 // Also saves all the containers to the database so that all required
 // information can be retrieved later if ever needed.
-func containerize(compose types.ComposeFile) {
+func containerize(compose types.ComposeFile, choices types.CarbonConfig, args []string) {
 	containers := []types.Container{}
 
 	for name, service := range compose.Services {
+		// Services that build their image get the tag they build it
+		// with while generating the compose file, but nothing stops a
+		// definition from having neither, and that's for compose to refuse.
+		image, _ := service["image"].(string)
+
 		container := types.Container{
 			ServiceName: name,
 			Name:        service["container_name"].(string),
-			Image:       service["image"].(string),
+			Image:       image,
 			Status:      "Created",
 			ComposeFile: compose.Path(),
+			CarbonFile:  choices[name].Path,
+			StartArgs:   args,
+			Host:        docker.DefaultHost(),
+		}
+
+		if store := choices[name].Store; store != nil {
+			container.StoreId = store.Id
 		}
+
 		container.Hash()
 
 		containers = append(containers, container)
@@ -238,3 +462,140 @@ func containerize(compose types.ComposeFile) {
 		database.AddContainer(container)
 	}
 }
+
+// Fills in everything about the containers within the given
+// compose file that only docker knows, now that they're running.
+//
+// This includes the ID that docker gave them and all of their
+// ports. Containers that docker doesn't know about are left alone
+// since they might have failed to start.
+func inspectStarted(compose types.ComposeFile) {
+	running := map[string]types.Container{}
+	for _, container := range docker.RunningContainers() {
+		running[container.Name] = container
+	}
+
+	for _, container := range database.Containers() {
+		if container.ComposeFile != compose.Path() {
+			continue
+		}
+
+		found, ok := running[container.Name]
+		if !ok {
+			continue
+		}
+
+		container.DockerUid = found.DockerUid
+		container.Status = found.Status
+		container.Ports = found.Ports
+
+		database.AddContainer(container)
+	}
+}
+
+// Waits for all the services within the given compose file
+// to become ready, or for the timeout to run out, showing every
+// change in their state along the way.
+//
+// A service is ready once its health check passes, or as soon as
+// it's running if it doesn't have one. Services that exit while
+// we're waiting won't become ready on their own, so they fail right
+// away instead of holding everything up until the timeout. The same
+// goes for services whose container doesn't exist at all, while any
+// other error only shows up if the service still isn't ready once
+// the timeout runs out.
+//
+// Returns the names of all the services that never became ready.
+func waitFor(compose types.ComposeFile, timeout time.Duration) []string {
+	printer.Extra(printer.Green, fmt.Sprintf("Waiting up to %s for the services to be ready", timeout))
+
+	pending := map[string]string{}
+	names := []string{}
+
+	for name, service := range compose.Services {
+		pending[name] = service["container_name"].(string)
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	failed := []string{}
+	seen := map[string]string{}
+	errs := map[string]error{}
+	started := time.Now()
+
+	for {
+		for _, name := range names {
+			container, ok := pending[name]
+			if !ok {
+				continue
+			}
+
+			state, err := docker.Inspect(container)
+			if errors.Is(err, docker.ErrNotFound) {
+				printer.Extra(printer.Red, fmt.Sprintf("'%s' has no container", name))
+				failed = append(failed, name)
+				delete(pending, name)
+				continue
+			}
+
+			if err != nil {
+				errs[name] = err
+				continue
+			}
+
+			delete(errs, name)
+
+			elapsed := time.Since(started).Round(time.Second)
+
+			switch {
+			case state.Health == "healthy" || (state.Health == "" && state.Running):
+				printer.Extra(printer.Green, fmt.Sprintf("'%s' is ready after %s", name, elapsed))
+				delete(pending, name)
+			case state.Status == "exited" || state.Status == "dead":
+				printer.Extra(printer.Red, fmt.Sprintf("'%s' exited with code %d", name, state.ExitCode))
+				failed = append(failed, name)
+				delete(pending, name)
+			case seen[name] != state.Status+state.Health:
+				printer.Extra(printer.Grey, fmt.Sprintf("'%s' is %s", name, progress(state)))
+			}
+
+			seen[name] = state.Status + state.Health
+		}
+
+		if len(pending) == 0 {
+			break
+		}
+
+		if time.Since(started) >= timeout {
+			for _, name := range names {
+				if _, ok := pending[name]; !ok {
+					continue
+				}
+
+				message := fmt.Sprintf("'%s' wasn't ready within %s", name, timeout)
+				if err, ok := errs[name]; ok {
+					message += ", last error: " + err.Error()
+				}
+
+				printer.Extra(printer.Red, message)
+				failed = append(failed, name)
+			}
+
+			break
+		}
+
+		time.Sleep(waitInterval)
+	}
+
+	sort.Strings(failed)
+	return failed
+}
+
+// Describes the state of a container that isn't ready yet.
+func progress(state types.ContainerState) string {
+	if state.Health != "" {
+		return state.Status + ", health check " + state.Health
+	}
+
+	return state.Status
+}
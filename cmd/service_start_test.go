@@ -2,21 +2,28 @@ package cmd
 
 import (
 	"co2/database"
+	"co2/docker"
+	"co2/helpers"
 	"co2/types"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/4khara/replica"
+	dockerTypes "github.com/docker/docker/api/types"
 )
 
 func mockStores() []types.Store {
 	return []types.Store{
 		{
 			Path: "foo",
-			Env:  "FOOPATH",
+			Envs: []string{"FOOPATH"},
 		},
 		{
 			Path: "bar",
-			Env:  "BARPATH",
+			Envs: []string{"BARPATH"},
 		},
 	}
 }
@@ -59,7 +66,7 @@ func mockCarbonConfig() types.CarbonConfig {
 func TestRunExecutesTheBuiltCommandWithAllServicesInOne(t *testing.T) {
 	beforeCmdTest()
 
-	envs, file, _ := compose(mockCarbonConfig())
+	envs, file, _ := compose(mockCarbonConfig(), nil)
 
 	run(file, envs, []string{"foo", "bar", "baz"})
 
@@ -80,9 +87,11 @@ func TestShouldRunWhenServicesFound(t *testing.T) {
 	// Add some containers to the database
 	containers := []types.Container{
 		{
+			Name:        "foo-container",
 			ServiceName: "foo",
 		},
 		{
+			Name:        "bar-container",
 			ServiceName: "bar",
 		},
 	}
@@ -103,9 +112,11 @@ func TestShouldRunWhenAnyServicesNotFound(t *testing.T) {
 	// Add some containers to the database
 	containers := []types.Container{
 		{
+			Name:        "foo-container",
 			ServiceName: "foo",
 		},
 		{
+			Name:        "bar-container",
 			ServiceName: "bar",
 		},
 	}
@@ -179,7 +190,7 @@ func TestExtractOverridesContainerName(t *testing.T) {
 func TestComposeReturnsErrorIfNoServicesAreFound(t *testing.T) {
 	beforeCmdTest()
 
-	_, _, err := compose(types.CarbonConfig{})
+	_, _, err := compose(types.CarbonConfig{}, nil)
 	if err == nil {
 		t.Error("compose should return error when no services are found")
 	}
@@ -188,7 +199,7 @@ func TestComposeReturnsErrorIfNoServicesAreFound(t *testing.T) {
 func TestComposeReturnsTheRightAmountOfEnvironmentFilePaths(t *testing.T) {
 	beforeCmdTest()
 
-	paths, _, _ := compose(mockCarbonConfig())
+	paths, _, _ := compose(mockCarbonConfig(), nil)
 
 	if len(paths) != 2 {
 		t.Error("compose should return 2 paths when 2 services are found")
@@ -205,7 +216,7 @@ func TestComposeReturnsTheRightFile(t *testing.T) {
 	custom["foo"] = m["foo"]
 	custom["bar"] = m["bar"]
 
-	paths, config, _ := compose(custom)
+	paths, config, _ := compose(custom, nil)
 
 	if len(config.Services) != 2 || len(config.Services) != len(paths) {
 		t.Error("compose should return 2 env files when 2 services are found with different files are found")
@@ -222,7 +233,7 @@ func TestComposeDoesNotDuplicateEnvironmentPathsThatAlreadyExist(t *testing.T) {
 	custom["foo"] = m["foo"]
 	custom["baz"] = m["baz"]
 
-	paths, _, _ := compose(custom)
+	paths, _, _ := compose(custom, nil)
 
 	if len(paths) != 1 {
 		t.Error("compose should return 1 env files when 2 services with the same env file are found got", len(paths))
@@ -232,14 +243,14 @@ func TestComposeDoesNotDuplicateEnvironmentPathsThatAlreadyExist(t *testing.T) {
 func TestContainerizeAddsAllServicesInComposeFileAsContainers(t *testing.T) {
 	beforeCmdTest()
 
-	_, file, _ := compose(mockCarbonConfig())
+	_, file, _ := compose(mockCarbonConfig(), nil)
 
 	// Make sure there are no containers in the database
 	if len(database.Containers()) != 0 {
 		t.Error("database should be empty before containerize is called")
 	}
 
-	containerize(file)
+	containerize(file, mockCarbonConfig(), []string{"foo", "bar", "baz"})
 
 	// Make sure there are containers in the database
 	if len(database.Containers()) != len(mockCarbonConfig()) {
@@ -250,14 +261,14 @@ func TestContainerizeAddsAllServicesInComposeFileAsContainers(t *testing.T) {
 func TestContainerizeHashesAllContainers(t *testing.T) {
 	beforeCmdTest()
 
-	_, file, _ := compose(mockCarbonConfig())
+	_, file, _ := compose(mockCarbonConfig(), nil)
 
 	// Make sure there are no containers in the database
 	if len(database.Containers()) != 0 {
 		t.Error("database should be empty before containerize is called")
 	}
 
-	containerize(file)
+	containerize(file, mockCarbonConfig(), []string{"foo", "bar", "baz"})
 
 	// Make sure all containers have a hash
 	for _, container := range database.Containers() {
@@ -270,14 +281,14 @@ func TestContainerizeHashesAllContainers(t *testing.T) {
 func TestContainerizeInjectsTheComposeFilePathIntoEachContainer(t *testing.T) {
 	beforeCmdTest()
 
-	_, file, _ := compose(mockCarbonConfig())
+	_, file, _ := compose(mockCarbonConfig(), nil)
 
 	// Make sure there are no containers in the database
 	if len(database.Containers()) != 0 {
 		t.Error("database should be empty before containerize is called")
 	}
 
-	containerize(file)
+	containerize(file, mockCarbonConfig(), []string{"foo", "bar", "baz"})
 
 	// Make sure all containers have a hash
 	for _, container := range database.Containers() {
@@ -286,3 +297,441 @@ func TestContainerizeInjectsTheComposeFilePathIntoEachContainer(t *testing.T) {
 		}
 	}
 }
+
+func TestComposeGivesEachServiceOnlyTheEnvFilesOfItsOwnStore(t *testing.T) {
+	beforeCmdTest()
+
+	_, file, _ := compose(mockCarbonConfig(), nil)
+
+	foo := file.Services["foo"]["env_file"].([]string)
+	bar := file.Services["bar"]["env_file"].([]string)
+
+	if len(foo) != 1 || foo[0] != "FOOPATH" {
+		t.Error("compose should only give foo the env files of its own store, got", foo)
+	}
+
+	if len(bar) != 1 || bar[0] != "BARPATH" {
+		t.Error("compose should only give bar the env files of its own store, got", bar)
+	}
+}
+
+func TestContainerizeLinksContainersToTheirStore(t *testing.T) {
+	beforeCmdTest()
+
+	store := database.AddStore(types.Store{Uid: "uid1", Path: "/code"})
+	config := mockCarbonConfig()
+
+	for name, service := range config {
+		service.Store = &store
+		config[name] = service
+	}
+
+	_, file, _ := compose(config, nil)
+	containerize(file, config, []string{"foo", "bar", "baz"})
+
+	for _, container := range database.Containers() {
+		if container.StoreId != store.Id {
+			t.Error("container should be linked to the store it was started from, got", container.StoreId)
+		}
+	}
+}
+
+func TestContainerizeRemembersTheStartArguments(t *testing.T) {
+	beforeCmdTest()
+
+	_, file, _ := compose(mockCarbonConfig(), nil)
+	containerize(file, mockCarbonConfig(), []string{"foo", "bar", "baz"})
+
+	for _, container := range database.Containers() {
+		if len(container.StartArgs) != 3 {
+			t.Error("container should remember the arguments it was started with, got", container.StartArgs)
+		}
+	}
+}
+
+func TestInspectStartedFillsInThePortsFromDocker(t *testing.T) {
+	beforeCmdTest()
+
+	_, file, _ := compose(mockCarbonConfig(), nil)
+	containerize(file, mockCarbonConfig(), []string{"foo", "bar", "baz"})
+
+	replica.Mocks.SetReturnValues("RunningContainers", []dockerTypes.Container{
+		{
+			ID:    helpers.Hash("foo", 30),
+			Image: "something",
+			Names: []string{"/" + file.Services["foo"]["container_name"].(string)},
+			Ports: []dockerTypes.Port{
+				{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+			},
+		},
+	})
+
+	inspectStarted(file)
+
+	for _, container := range database.Containers() {
+		if container.ServiceName != "foo" {
+			continue
+		}
+
+		if len(container.Ports) != 1 || container.Ports[0].HostPort != 8080 {
+			t.Error("inspectStarted should save the ports docker knows about, got", container.Ports)
+		}
+
+		if container.DockerUid != helpers.Hash("foo", 30) {
+			t.Error("inspectStarted should save the docker id, got", container.DockerUid)
+		}
+	}
+}
+
+func TestPortConflictsFindsPortsUsedByOtherCarbonContainers(t *testing.T) {
+	beforeCmdTest()
+
+	database.AddContainer(types.Container{
+		Name:        "db-container",
+		ServiceName: "db",
+		Ports: types.Ports{
+			{HostPort: 5432, ContainerPort: 5432, Protocol: "tcp"},
+		},
+	})
+
+	choices := types.CarbonConfig{
+		"other-db": types.CarbonService{
+			Name: "other-db",
+			FullContents: types.ServiceFields{
+				"ports": []interface{}{"5432:5432"},
+			},
+		},
+		"api": types.CarbonService{
+			Name: "api",
+			FullContents: types.ServiceFields{
+				"ports": []interface{}{"8080:80"},
+			},
+		},
+	}
+
+	conflicts := portConflicts(choices)
+
+	if len(conflicts) != 1 {
+		t.Error("portConflicts should find the one conflicting port, got", conflicts)
+	}
+}
+
+func TestComposeLabelsEveryService(t *testing.T) {
+	beforeCmdTest()
+
+	config := mockCarbonConfig()
+	foo := config["foo"]
+	foo.Path = "/code/foo/carbon.yml"
+	foo.Store = &types.Store{Uid: "uid1", Path: "/code"}
+	config["foo"] = foo
+
+	_, file, _ := compose(config, []string{"foo", "bar", "baz"})
+
+	labels := file.Services["foo"]["labels"].(map[string]string)
+	expected := map[string]string{
+		types.LabelService:     "foo",
+		types.LabelStore:       "uid1",
+		types.LabelCarbonFile:  "/code/foo/carbon.yml",
+		types.LabelComposeFile: file.Path(),
+		types.LabelStartArgs:   "foo bar baz",
+	}
+
+	for key, value := range expected {
+		if labels[key] != value {
+			t.Errorf("compose should label %s with %q, got %q", key, value, labels[key])
+		}
+	}
+
+	if labels[types.LabelStartedAt] == "" {
+		t.Error("compose should label when the service was started")
+	}
+}
+
+func TestComposeKeepsTheLabelsOfTheService(t *testing.T) {
+	beforeCmdTest()
+
+	config := mockCarbonConfig()
+	config["bar"].FullContents["labels"] = []interface{}{"team=core"}
+
+	_, file, _ := compose(config, nil)
+
+	labels := file.Services["bar"]["labels"].(map[string]string)
+
+	if labels["team"] != "core" || labels[types.LabelService] != "bar" {
+		t.Error("compose should merge the carbon labels into the existing ones, got", labels)
+	}
+}
+
+func TestWaitForSucceedsOnceServicesAreRunning(t *testing.T) {
+	beforeCmdTest()
+
+	_, file, _ := compose(mockCarbonConfig(), nil)
+
+	if failed := waitFor(file, time.Second); len(failed) != 0 {
+		t.Error("waitFor should accept running services without a health check, got", failed)
+	}
+}
+
+func TestWaitForNamesServicesThatNeverBecomeHealthy(t *testing.T) {
+	beforeCmdTest()
+
+	waitInterval = time.Millisecond
+	defer func() { waitInterval = 500 * time.Millisecond }()
+
+	_, file, _ := compose(mockCarbonConfig(), nil)
+
+	replica.Mocks.SetReturnValues("Inspect", dockerTypes.ContainerJSON{
+		ContainerJSONBase: &dockerTypes.ContainerJSONBase{
+			State: &dockerTypes.ContainerState{
+				Status:  "running",
+				Running: true,
+				Health:  &dockerTypes.Health{Status: "starting"},
+			},
+		},
+	}, nil)
+
+	failed := waitFor(file, 10*time.Millisecond)
+
+	if len(failed) != 3 || failed[0] != "bar" {
+		t.Error("waitFor should name every service that never became healthy, got", failed)
+	}
+}
+
+func TestWaitForFailsExitedServicesRightAway(t *testing.T) {
+	beforeCmdTest()
+
+	_, file, _ := compose(mockCarbonConfig(), nil)
+
+	replica.Mocks.SetReturnValues("Inspect", dockerTypes.ContainerJSON{
+		ContainerJSONBase: &dockerTypes.ContainerJSONBase{
+			State: &dockerTypes.ContainerState{Status: "exited", ExitCode: 1},
+		},
+	}, nil)
+
+	start := time.Now()
+	failed := waitFor(file, time.Hour)
+
+	if len(failed) != 3 {
+		t.Error("waitFor should fail services that exited, got", failed)
+	}
+
+	if time.Since(start) > time.Second {
+		t.Error("waitFor shouldn't wait for the timeout when services have exited")
+	}
+}
+
+func TestWaitForFailsMissingContainersRightAway(t *testing.T) {
+	beforeCmdTest()
+
+	_, file, _ := compose(mockCarbonConfig(), nil)
+
+	replica.Mocks.SetReturnValues("Inspect", dockerTypes.ContainerJSON{}, notFoundError{})
+
+	start := time.Now()
+	failed := waitFor(file, time.Hour)
+
+	if len(failed) != 3 {
+		t.Error("waitFor should fail services without a container, got", failed)
+	}
+
+	if time.Since(start) > time.Second {
+		t.Error("waitFor shouldn't wait for the timeout when containers are missing")
+	}
+}
+
+func TestWaitForShowsTheLastErrorOnTimeout(t *testing.T) {
+	beforeCmdTest()
+
+	waitInterval = time.Millisecond
+	defer func() { waitInterval = 500 * time.Millisecond }()
+
+	_, file, _ := compose(mockCarbonConfig(), nil)
+
+	replica.Mocks.SetReturnValues("Inspect", dockerTypes.ContainerJSON{}, errors.New("daemon went away"))
+
+	if failed := waitFor(file, 10*time.Millisecond); len(failed) != 3 {
+		t.Error("waitFor should fail services it couldn't inspect, got", failed)
+	}
+
+	printed := ""
+	for _, params := range replica.Mocks.GetCallParams("Ln") {
+		printed += fmt.Sprint(params...)
+	}
+
+	if !strings.Contains(printed, "last error: daemon went away") {
+		t.Error("waitFor should show the last error it got, got", printed)
+	}
+}
+
+func TestStartHostUsesTheHostOfTheStores(t *testing.T) {
+	beforeCmdTest()
+
+	store := types.Store{Uid: "uid1", Path: "/code", Host: "ssh://dev@remote"}
+	config := mockCarbonConfig()
+
+	for name, service := range config {
+		service.Store = &store
+		config[name] = service
+	}
+
+	host, err := startHost(config)
+
+	if err != nil || host != "ssh://dev@remote" {
+		t.Error("startHost should use the host of the stores, got", host, err)
+	}
+}
+
+func TestStartHostRefusesServicesFromDifferentHosts(t *testing.T) {
+	beforeCmdTest()
+
+	config := mockCarbonConfig()
+	foo := config["foo"]
+	foo.Store = &types.Store{Uid: "uid1", Path: "/code", Host: "ssh://dev@remote"}
+	config["foo"] = foo
+
+	if _, err := startHost(config); err == nil {
+		t.Error("startHost should refuse services from different docker hosts")
+	}
+}
+
+func TestStartHostPrefersTheRequestedHost(t *testing.T) {
+	beforeCmdTest()
+
+	config := mockCarbonConfig()
+	foo := config["foo"]
+	foo.Store = &types.Store{Uid: "uid1", Path: "/code", Host: "ssh://dev@remote"}
+	config["foo"] = foo
+
+	docker.UseHost("tcp://10.0.0.2:2375")
+	host, err := startHost(config)
+
+	if err != nil || host != "tcp://10.0.0.2:2375" {
+		t.Error("startHost should use the requested host over the ones of the stores, got", host, err)
+	}
+}
+
+func TestPortConflictsIgnoreContainersOnOtherHosts(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	database.AddContainer(types.Container{
+		Name:        "web-remote",
+		ServiceName: "web",
+		Host:        "ssh://dev@remote",
+		Ports:       types.Ports{{HostPort: 8080, ContainerPort: 80, Protocol: "tcp"}},
+	})
+
+	config := mockCarbonConfig()
+	config["foo"].FullContents["ports"] = []interface{}{"8080:80"}
+
+	if conflicts := portConflicts(config); len(conflicts) != 0 {
+		t.Error("portConflicts should ignore containers on other docker hosts, got", conflicts)
+	}
+}
+
+func TestContainerizeRemembersTheHost(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	docker.UseHost("ssh://dev@remote")
+
+	_, file, _ := compose(mockCarbonConfig(), nil)
+	containerize(file, mockCarbonConfig(), []string{"foo", "bar", "baz"})
+
+	for _, container := range database.Containers() {
+		if container.Host != "ssh://dev@remote" {
+			t.Error("container should remember the docker host it runs on, got", container.Host)
+		}
+	}
+}
+
+func mockBuildConfig() types.CarbonConfig {
+	config := mockCarbonConfig()
+
+	foo := config["foo"]
+	foo.Path = "/code/foo/carbon.yml"
+	foo.Build = types.BuildConfig{Context: "./app", Dockerfile: "Dockerfile.dev"}
+	delete(foo.FullContents, "image")
+	foo.FullContents["build"] = map[interface{}]interface{}{"context": "./app"}
+	config["foo"] = foo
+
+	return config
+}
+
+func TestComposePointsTheBuildSectionAtTheCarbonFile(t *testing.T) {
+	beforeCmdTest()
+
+	_, file, _ := compose(mockBuildConfig(), nil)
+
+	foo := file.Services["foo"]
+	if foo["image"] != "co2/foo:latest" {
+		t.Error("compose should give build only services their generated tag, got", foo["image"])
+	}
+
+	build := foo["build"].(map[string]interface{})
+	if build["context"] != "/code/foo/app" || build["dockerfile"] != "Dockerfile.dev" {
+		t.Error("compose should resolve the build context from the carbon file, got", build)
+	}
+
+	if _, ok := file.Services["bar"]["build"]; ok {
+		t.Error("compose shouldn't add a build section to services that don't build")
+	}
+}
+
+func TestContainerizeStoresTheTagOfBuildOnlyServices(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	config := mockBuildConfig()
+	_, file, _ := compose(config, nil)
+
+	containerize(file, config, []string{"foo", "bar", "baz"})
+
+	for _, container := range database.Containers() {
+		if container.ServiceName == "foo" && container.Image != "co2/foo:latest" {
+			t.Error("containerize should store the generated tag, got", container.Image)
+		}
+	}
+}
+
+func TestComposeJoinsEveryServiceToTheSharedNetwork(t *testing.T) {
+	beforeCmdTest()
+
+	config := mockCarbonConfig()
+	config["baz"].FullContents["network_mode"] = "host"
+
+	_, file, _ := compose(config, nil)
+
+	if !file.Networks["carbon"].External {
+		t.Error("compose should add the shared network as an external one, got", file.Networks)
+	}
+
+	networks := file.Services["foo"]["networks"].(map[string]interface{})
+	if _, ok := networks["default"]; !ok {
+		t.Error("compose should keep the default network, got", networks)
+	}
+
+	aliases := networks["carbon"].(map[string]interface{})["aliases"].([]string)
+	if len(aliases) != 1 || aliases[0] != "foo" {
+		t.Error("compose should alias the service by its name, got", aliases)
+	}
+
+	if _, ok := file.Services["baz"]["networks"]; ok {
+		t.Error("compose shouldn't add networks to services with a network mode")
+	}
+}
+
+func TestRunPassesEveryServiceAsItsOwnArgument(t *testing.T) {
+	beforeCmdTest()
+
+	envs, file, _ := compose(mockCarbonConfig(), nil)
+
+	run(file, envs, []string{"foo", "bar"})
+
+	args := replica.Mocks.GetCallParams("Execute")[0][1].(types.Command).Args
+	last := args[len(args)-2:]
+
+	if last[0] != "foo" || last[1] != "bar" {
+		t.Error("run should pass the services as separate arguments, got", args)
+	}
+}
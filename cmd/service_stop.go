@@ -16,7 +16,7 @@ var stopCmd = &cobra.Command{
 	Use:   "stop",
 	Short: "Stops the provided services",
 	Args:  cobra.MinimumNArgs(1),
-	Run:   execStop,
+	Run:   locked(execStop),
 }
 
 // Handle the stopping of the provided running containers.
@@ -66,24 +66,33 @@ func groupByComposeFile(choices ...string) map[string][]types.Container {
 // Builds a new docker compose stop command for each provided
 // compose file container group and then runs them all in parallel after
 // deleting all the containers from the database.
+//
+// Once they've stopped, the logs of all of them get archived.
 func stopContainers(groups map[string][]types.Container) {
 	commands := []types.Command{}
+	stopped := []types.Container{}
 
 	for _, composeFile := range groups {
 		command := builder.DockerComposeCommand().
+			Host(composeFile[0].Host).
 			File(composeFile[0].ComposeFile).
 			Stop()
 
 		for _, container := range composeFile {
 			command.Service(container.ServiceName)
 			database.DeleteContainer(container)
+
+			stopped = append(stopped, container)
 		}
 
 		commands = append(commands, types.Command{
 			Text: command.Build(),
+			Args: command.Args(),
 		})
 	}
 
 	printer.Extra(printer.Green, "Executing stop commands")
 	runner.Execute(commands...)
+
+	archiveLogs(stopped)
 }
@@ -5,8 +5,10 @@ import (
 	"co2/database"
 	"co2/docker"
 	"co2/printer"
+	"co2/types"
 	"fmt"
 
+	"github.com/kballard/go-shellquote"
 	"github.com/spf13/cobra"
 )
 
@@ -44,17 +46,23 @@ func init() {
 // can be piped into something else.
 func execShell(cmd *cobra.Command, args []string) {
 	toRun := args[0]
-	shell := "/bin/bash"
+	shell := []string{"/bin/bash"}
 
 	if sh {
-		shell = "/bin/sh"
+		shell = []string{"/bin/sh"}
 	}
 
 	if custom != "" {
-		shell = custom
+		split, err := shellquote.Split(custom)
+		if err != nil {
+			printer.Error("ERROR", "couldn't read the custom shell:", err.Error())
+			return
+		}
+
+		shell = split
 	}
 
-	command := generateShellCommand(toRun, shell)
+	command := generateShellCommand(toRun, shell...)
 	if command != "" {
 		fmt.Println(command)
 		return
@@ -69,32 +77,43 @@ func execShell(cmd *cobra.Command, args []string) {
 //
 // The identifier can be either a custom Uid generated by carbon,
 // a carbon service name, or a docker container name.
-func generateShellCommand(ident, shell string) string {
-	found := byDocker(ident)
-
-	if found == "" {
-		found = byCarbon(ident)
-	}
-
-	if found == "" {
+func generateShellCommand(ident string, shell ...string) string {
+	found := findContainer(ident)
+	if found.Name == "" {
 		return ""
 	}
 
 	cmd := builder.DockerShellCommand().
-		Container(found).
-		Shell(shell).
+		Host(found.Host).
+		Container(found.Name).
+		Shell(shell...).
 		Build()
 
 	return cmd
 }
 
+// Finds the container the given identifier belongs to, looking
+// at the running docker containers first and at the carbon
+// services after that.
+//
+// If nothing matches, an empty container is returned.
+func findContainer(ident string) types.Container {
+	found := byDocker(ident)
+
+	if found.Name == "" {
+		found = byCarbon(ident)
+	}
+
+	return found
+}
+
 // Looks at all the containers within the database and
 // compares their carbon defined service name with the
 // provided container identifier.
 //
-// If anything matches, the container name will be returned
-// otherwise just an empty string.
-func byCarbon(ident string) string {
+// If anything matches, the container will be returned
+// otherwise just an empty one.
+func byCarbon(ident string) types.Container {
 	containers := database.Containers()
 
 	for _, container := range containers {
@@ -102,19 +121,19 @@ func byCarbon(ident string) string {
 			continue
 		}
 
-		return container.Name
+		return container
 	}
 
-	return ""
+	return types.Container{}
 }
 
 // Looks at all the running containers, carbon or not,
 // and comparse their generated Uid and Name with the
 // provided identifier.
 //
-// If anything matches, the container name will be returned
-// otherwise just an empty string.
-func byDocker(ident string) string {
+// If anything matches, the container will be returned
+// otherwise just an empty one.
+func byDocker(ident string) types.Container {
 	containers := docker.RunningContainers()
 
 	for _, container := range containers {
@@ -122,8 +141,8 @@ func byDocker(ident string) string {
 			continue
 		}
 
-		return container.Name
+		return container
 	}
 
-	return ""
+	return types.Container{}
 }
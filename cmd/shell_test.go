@@ -103,7 +103,7 @@ func TestByCarbonFindsByServiceName(t *testing.T) {
 	// Generate the command with the service name
 	command := byCarbon("service1")
 
-	if command == "" {
+	if command.Name == "" {
 		t.Error("byCarbon should return a command when given a service name")
 	}
 }
@@ -117,7 +117,7 @@ func TestByDockerFindsByUid(t *testing.T) {
 	// Generate the command with the Uid
 	command := byDocker(container.Uid)
 
-	if command == "" {
+	if command.Name == "" {
 		t.Error("byDocker should return a command when given a Uid")
 	}
 }
@@ -131,7 +131,7 @@ func TestByDockerFindsByName(t *testing.T) {
 	// Generate the command with the name
 	command := byDocker(container.Name)
 
-	if command == "" {
+	if command.Name == "" {
 		t.Error("byDocker should return a command when given a name")
 	}
 }
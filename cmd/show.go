@@ -3,9 +3,13 @@ package cmd
 import (
 	"co2/database"
 	"co2/docker"
+	"co2/helpers"
 	"co2/printer"
+	"co2/types"
+	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
@@ -16,9 +20,13 @@ import (
 type showFunction func() (printer.Table, string)
 
 var (
-	running   bool
-	stores    bool
-	available bool
+	running    bool
+	stores     bool
+	available  bool
+	carbonOnly bool
+	everything bool
+	links      bool
+	jsonOutput bool
 
 	showCmd = &cobra.Command{
 		Use:   "show",
@@ -31,6 +39,11 @@ var (
 	// Still visible, but less important.
 	fadedStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#777777"))
+
+	// Containers that carbon has lost track of need
+	// to stand out from the rest.
+	staleStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(printer.Red))
 )
 
 // Adds all the required flags
@@ -38,14 +51,41 @@ func init() {
 	showCmd.Flags().BoolVarP(&running, "running", "r", false, "show all currently running containers")
 	showCmd.Flags().BoolVarP(&stores, "stores", "s", false, "show all registered stores")
 	showCmd.Flags().BoolVarP(&available, "carbon", "c", false, "show all available carbon services")
+	showCmd.Flags().BoolVar(&carbonOnly, "carbon-only", false, "only show the running containers that carbon started")
+	showCmd.Flags().BoolVar(&everything, "all", false, "show all containers, including the ones that have exited")
+	showCmd.Flags().BoolVar(&links, "links", false, "show links to the published ports of the containers")
+	showCmd.Flags().BoolVar(&jsonOutput, "json", false, "output the containers as json instead of a table")
+}
+
+// How a single container looks in the json output,
+// which is meant for scripts more than for people.
+type containerJson struct {
+	Key     string      `json:"key"`
+	Name    string      `json:"name"`
+	Id      string      `json:"id"`
+	Image   string      `json:"image"`
+	Status  string      `json:"status"`
+	Service string      `json:"service,omitempty"`
+	Store   string      `json:"store,omitempty"`
+	Host    string      `json:"host,omitempty"`
+	Ports   types.Ports `json:"ports"`
+	Links   []string    `json:"links"`
 }
 
 // Checks what flags are provided and displays
 // the specific table representing each flag.
+//
+// Json output is meant to be parsed, so when it's requested
+// the containers are the only thing that gets printed.
 func execShow(cmd *cobra.Command, args []string) {
+	if jsonOutput {
+		showJson()
+		return
+	}
+
 	functions := []showFunction{}
 
-	if running {
+	if running || everything {
 		functions = append(functions, showRunning)
 	}
 
@@ -75,46 +115,228 @@ func execShow(cmd *cobra.Command, args []string) {
 //
 // The resulting table should also contain the unique
 // id for the container generated from the name and the image.
+//
+// With `--all`, containers that aren't running anymore are shown as
+// well, along with how they exited. The ones carbon still thinks are
+// running are highlighted since something happened to them behind its back.
+//
+// Unless a specific docker host is requested, this looks at every
+// host carbon knows about. Hosts that can't be reached are skipped
+// with a warning, and the table only shows which host a container
+// runs on if there's more than one of them.
 func showRunning() (printer.Table, string) {
 	var table printer.Table
-	containers := docker.RunningContainers()
+	containers, hosts := listContainers()
 
 	if len(containers) == 0 {
 		return table, printer.Render(printer.Cyan, "RUN", "No running containers", "")
 	}
 
-	sort.Slice(containers, func(i, j int) bool {
-		return containers[i].Name < containers[j].Name
-	})
+	header := []string{"KEY", "NAME", "ID", "IMAGE", "PORTS", "CREATED", "STATUS"}
+	if everything {
+		header = append(header, "EXIT", "FINISHED", "OOM")
+	}
 
-	table = printer.NewTable(7)
+	if links {
+		header = append(header, "LINKS")
+	}
+
+	if len(hosts) > 1 {
+		header = append(header, "HOST")
+	}
+
+	table = printer.NewTable(len(header))
 	printer.Info(printer.Cyan, "RUN", "total running containers:", fmt.Sprint(len(containers)))
 
-	table.Header(
-		"KEY",
-		"NAME",
-		"ID",
-		"IMAGE",
-		"PORTS",
-		"CREATED",
-		"STATUS",
-	)
+	table.Header(header...)
+
+	saved := map[string]types.Container{}
+	for _, container := range database.Containers() {
+		saved[container.Name] = container
+	}
 
 	for _, container := range containers {
-		table.Row(
+		name := container.Name
+		status := container.Status
+
+		if row, ok := saved[container.Name]; ok && !container.State.Running && everything && believedRunning(row) {
+			name = staleStyle.Render(name)
+			status += " (carbon thinks it's running)"
+		}
+
+		row := []string{
 			container.Uid,
-			container.Name,
+			name,
 			container.DockerUid[:10],
 			container.Image,
-			fadedStyle.Render(container.Ports),
+			fadedStyle.Render(container.Ports.String()),
 			fadedStyle.Render(fmt.Sprint(container.CreatedAt)),
-			fadedStyle.Render(container.Status),
-		)
+			fadedStyle.Render(status),
+		}
+
+		if everything {
+			row = append(row, exitColumns(container.State)...)
+		}
+
+		if links {
+			row = append(row, strings.Join(container.Ports.Links(), " "))
+		}
+
+		if len(hosts) > 1 {
+			row = append(row, fadedStyle.Render(hostName(container.Host)))
+		}
+
+		table.Row(row...)
 	}
 
 	return table, ""
 }
 
+// Prints all the containers that would be in the running
+// table as json instead, with all of their ports spelled out.
+func showJson() {
+	containers, _ := listContainers()
+	output := []containerJson{}
+
+	for _, container := range containers {
+		output = append(output, containerJson{
+			Key:     container.Uid,
+			Name:    container.Name,
+			Id:      container.DockerUid,
+			Image:   container.Image,
+			Status:  container.Status,
+			Service: container.Labels[types.LabelService],
+			Store:   container.Labels[types.LabelStore],
+			Host:    container.Host,
+			Ports:   container.Ports.Collapse(),
+			Links:   container.Ports.Links(),
+		})
+	}
+
+	encoded, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+
+	printer.Ln(string(encoded))
+}
+
+// Gets all the containers from every docker host that should be
+// shown, sorted by host and then by name, along with the hosts
+// they were taken from.
+//
+// Hosts that can't be reached are skipped, with a warning unless
+// the output is json, which has to stay parsable.
+//
+// Carbon labels every container it starts, so if only those
+// are requested, docker can do the filtering for us.
+func listContainers() ([]types.Container, []string) {
+	labels := []string{}
+
+	if carbonOnly {
+		labels = append(labels, types.LabelService)
+	}
+
+	hosts := knownHosts()
+	containers := []types.Container{}
+
+	list := docker.RunningContainersOn
+	if everything {
+		list = docker.AllContainersOn
+	}
+
+	for _, host := range hosts {
+		found, err := list(host, labels...)
+		if err != nil && jsonOutput {
+			continue
+		}
+
+		if err != nil {
+			printer.Error("ERROR", "couldn't reach docker host:", hostName(host))
+			printer.Extra(printer.Red, err.Error())
+			continue
+		}
+
+		containers = append(containers, found...)
+	}
+
+	sort.Slice(containers, func(i, j int) bool {
+		if containers[i].Host != containers[j].Host {
+			return containers[i].Host < containers[j].Host
+		}
+
+		return containers[i].Name < containers[j].Name
+	})
+
+	return containers, hosts
+}
+
+// Whether the database still thinks the given container is running,
+// which it does until carbon stops it or sees it exit.
+func believedRunning(container types.Container) bool {
+	return !strings.HasPrefix(container.Status, "Exited") && container.Status != "Out of memory"
+}
+
+// The exit code, finish time, and whether or not the container was
+// killed for running out of memory. Running containers have none of those.
+func exitColumns(state types.ContainerState) []string {
+	if state.Running || state.FinishedAt.IsZero() {
+		return []string{"-", "-", "-"}
+	}
+
+	oom := "no"
+	if state.OOMKilled {
+		oom = staleStyle.Render("yes")
+	}
+
+	return []string{
+		fmt.Sprint(state.ExitCode),
+		fadedStyle.Render(state.FinishedAt.Local().Format("2006-01-02 15:04:05")),
+		oom,
+	}
+}
+
+// Lists every docker host that carbon knows about, starting
+// with the default one.
+//
+// If a host was asked for explicitly, that's the only one. Otherwise
+// it's the default one along with every host a store or a container
+// has been linked to.
+func knownHosts() []string {
+	hosts := []string{docker.DefaultHost()}
+	if hosts[0] != "" {
+		return hosts
+	}
+
+	others := []string{}
+
+	for _, store := range database.Stores() {
+		if store.Host != "" && !helpers.Contains(others, store.Host) {
+			others = append(others, store.Host)
+		}
+	}
+
+	for _, container := range database.Containers() {
+		if container.Host != "" && !helpers.Contains(others, container.Host) {
+			others = append(others, container.Host)
+		}
+	}
+
+	sort.Strings(others)
+
+	return append(hosts, others...)
+}
+
+// The name to show for a docker host, since the
+// default one doesn't have any.
+func hostName(host string) string {
+	if host == "" {
+		return "default"
+	}
+
+	return host
+}
+
 // Generates a table of all the registered carbon stores.
 //
 // It's not that complicated, thankfully.
@@ -122,7 +344,7 @@ func showRunning() (printer.Table, string) {
 // the currently registered stores and then display it
 // in a nicely formatted table.
 //
-// If the stores don't have an environment file set,
+// If the stores don't have any environment files set,
 // this will replace the value with 'undefined' in the
 // resulting table.
 func showStores() (printer.Table, string) {
@@ -138,28 +360,36 @@ func showStores() (printer.Table, string) {
 		return stores[i].Path < stores[j].Path
 	})
 
-	table = printer.NewTable(4)
+	table = printer.NewTable(8)
 	printer.Info(printer.Grey, "STORE", "total registered stores:", fmt.Sprint(len(stores)))
 
 	table.Header(
 		"KEY",
 		"PATH",
+		"DEPTH",
+		"HOST",
 		"DATE",
 		"ENV",
+		"LABELS",
+		"DESCRIPTION",
 	)
 
 	for _, store := range stores {
 		env := "undefined"
 
-		if store.Env != "" {
-			env = store.Env
+		if len(store.Envs) > 0 {
+			env = strings.Join(store.Envs, ", ")
 		}
 
 		table.Row(
 			store.Uid,
 			store.Path,
+			fmt.Sprint(store.Depth),
+			hostName(store.Host),
 			fadedStyle.Render(fmt.Sprint(store.CreatedAt)),
 			env,
+			fadedStyle.Render(formatLabels(store.Labels)),
+			store.Description,
 		)
 	}
 
@@ -209,3 +439,17 @@ func showAvailable() (printer.Table, string) {
 
 	return table, ""
 }
+
+// Formats the given labels as a comma separated list of
+// `key=value` pairs, sorted by key so it's always the same.
+func formatLabels(labels map[string]string) string {
+	pairs := []string{}
+
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ", ")
+}
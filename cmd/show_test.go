@@ -2,13 +2,16 @@ package cmd
 
 import (
 	"co2/database"
+	"co2/docker"
 	"co2/helpers"
 	"co2/types"
+	"encoding/json"
 	"strings"
 	"testing"
 
 	"github.com/4khara/replica"
 	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
 )
 
 func TestShowRunningsReturnsErrorIfNoContainersAreRunning(t *testing.T) {
@@ -40,6 +43,119 @@ func TestRunningTableHasOneRowPerContainer(t *testing.T) {
 	}
 }
 
+func TestShowRunningOnlyAsksForCarbonContainersWhenRequested(t *testing.T) {
+	beforeCmdTest()
+
+	carbonOnly = true
+	defer func() { carbonOnly = false }()
+
+	showRunning()
+
+	args := replica.Mocks.GetCallParams("RunningContainers")[0][0].(filters.Args)
+
+	if !args.ExactMatch("label", types.LabelService) {
+		t.Error("showRunning should filter on the carbon labels, got", args.Get("label"))
+	}
+}
+
+func TestShowRunningListsEveryKnownHost(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	database.AddStore(types.Store{Uid: "uid1", Path: "path1", Host: "tcp://10.0.0.2:2375"})
+
+	res, _ := showRunning()
+	rows := res.Rows()
+
+	if len(rows) != 8 { // 3 containers on each host + 2 extra rows from the Header
+		t.Errorf("showRunning should list the containers of every host, got %d rows", len(rows))
+	}
+
+	if !strings.Contains(rows[0], "HOST") {
+		t.Error("showRunning should show the host of each container when there's more than one")
+	}
+}
+
+func TestShowRunningOnlyAsksTheRequestedHost(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	database.AddStore(types.Store{Uid: "uid1", Path: "path1", Host: "tcp://10.0.0.2:2375"})
+	docker.UseHost("tcp://10.0.0.3:2375")
+
+	res, _ := showRunning()
+
+	if len(res.Rows()) != 5 {
+		t.Error("showRunning should only list the containers of the requested host")
+	}
+
+	if strings.Contains(res.Rows()[0], "HOST") {
+		t.Error("showRunning shouldn't show the host column for a single host")
+	}
+}
+
+func TestShowAllIncludesHowContainersExited(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	everything = true
+	defer func() { everything = false }()
+
+	replica.Mocks.SetReturnValues("AllContainers", []dockerTypes.Container{
+		{ID: helpers.Hash("crashed", 30), Image: "image1", Names: []string{"/crashed"}, State: "exited", Status: "Exited (137)"},
+	})
+	replica.Mocks.SetReturnValues("Inspect", dockerTypes.ContainerJSON{
+		ContainerJSONBase: &dockerTypes.ContainerJSONBase{
+			State: &dockerTypes.ContainerState{
+				Status:     "exited",
+				ExitCode:   137,
+				OOMKilled:  true,
+				FinishedAt: "2022-01-02T10:00:00Z",
+			},
+		},
+	}, nil)
+
+	res, _ := showRunning()
+	rows := res.Rows()
+
+	if !strings.Contains(rows[0], "EXIT") || !strings.Contains(rows[0], "OOM") {
+		t.Error("showRunning should show how containers exited with `--all`, got", rows[0])
+	}
+
+	if !strings.Contains(rows[2], "137") || !strings.Contains(rows[2], "yes") {
+		t.Error("showRunning should show the exit code and the oom kill, got", rows[2])
+	}
+
+	if strings.Contains(rows[2], "carbon thinks") {
+		t.Error("showRunning shouldn't highlight containers carbon doesn't know about")
+	}
+}
+
+func TestShowAllHighlightsContainersCarbonThinksAreRunning(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	everything = true
+	defer func() { everything = false }()
+
+	database.AddContainer(types.Container{Name: "crashed", ServiceName: "api", Status: "Up"})
+
+	replica.Mocks.SetReturnValues("AllContainers", []dockerTypes.Container{
+		{ID: helpers.Hash("crashed", 30), Image: "image1", Names: []string{"/crashed"}, State: "exited", Status: "Exited (1)"},
+	})
+	replica.Mocks.SetReturnValues("Inspect", dockerTypes.ContainerJSON{
+		ContainerJSONBase: &dockerTypes.ContainerJSONBase{
+			State: &dockerTypes.ContainerState{Status: "exited", ExitCode: 1},
+		},
+	}, nil)
+
+	res, _ := showRunning()
+
+	if !strings.Contains(res.Rows()[2], "carbon thinks it's running") {
+		t.Error("showRunning should highlight containers carbon still thinks are running, got", res.Rows()[2])
+	}
+}
+
 func TestShowRunningSortsByContainerName(t *testing.T) {
 	beforeCmdTest()
 
@@ -102,9 +218,9 @@ func TestShowStoresShowsOneRowPerStore(t *testing.T) {
 	defer afterCmdTest()
 
 	// Add some stores to the database
-	database.AddStore(types.Store{})
-	database.AddStore(types.Store{})
-	database.AddStore(types.Store{})
+	database.AddStore(types.Store{Uid: "uid1", Path: "store1"})
+	database.AddStore(types.Store{Uid: "uid2", Path: "store2"})
+	database.AddStore(types.Store{Uid: "uid3", Path: "store3"})
 
 	// Show stores
 	res, _ := showStores()
@@ -119,9 +235,9 @@ func TestShowStoresSortsByStorePath(t *testing.T) {
 	defer afterCmdTest()
 
 	// Add some stores to the database
-	database.AddStore(types.Store{Path: "store1"})
-	database.AddStore(types.Store{Path: "store2"})
-	database.AddStore(types.Store{Path: "store3"})
+	database.AddStore(types.Store{Uid: "uid1", Path: "store1"})
+	database.AddStore(types.Store{Uid: "uid2", Path: "store2"})
+	database.AddStore(types.Store{Uid: "uid3", Path: "store3"})
 
 	// Show stores
 	res, _ := showStores()
@@ -226,3 +342,67 @@ func TestShowAvailableSortsByCarbonServiceName(t *testing.T) {
 		t.Error("showAvailable should sort the Carbon service names")
 	}
 }
+
+func TestShowRunningShowsLinksWhenRequested(t *testing.T) {
+	beforeCmdTest()
+
+	links = true
+	defer func() { links = false }()
+
+	replica.Mocks.SetReturnValues("RunningContainers", []dockerTypes.Container{
+		{
+			ID:    helpers.Hash("web", 30),
+			Image: "nginx",
+			Names: []string{"/web"},
+			Ports: []dockerTypes.Port{
+				{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+				{IP: "::", PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+			},
+		},
+	})
+
+	res, _ := showRunning()
+	rows := res.Rows()
+
+	if !strings.Contains(rows[0], "LINKS") || !strings.Contains(rows[2], "http://localhost:8080") {
+		t.Error("showRunning should show links to the published ports, got", rows[2])
+	}
+
+	if strings.Count(rows[2], "8080->80/tcp") != 1 {
+		t.Error("showRunning should collapse the same port published on IPv4 and IPv6, got", rows[2])
+	}
+}
+
+func TestShowJsonPrintsTheStructuredPorts(t *testing.T) {
+	beforeCmdTest()
+
+	replica.Mocks.SetReturnValues("RunningContainers", []dockerTypes.Container{
+		{
+			ID:     helpers.Hash("web", 30),
+			Image:  "nginx",
+			Names:  []string{"/web"},
+			Labels: map[string]string{types.LabelService: "web"},
+			Ports: []dockerTypes.Port{
+				{IP: "127.0.0.1", PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+			},
+		},
+	})
+
+	showJson()
+
+	printed := replica.Mocks.GetCallParams("Ln")[0][0].(string)
+
+	var parsed []containerJson
+	if err := json.Unmarshal([]byte(printed), &parsed); err != nil {
+		t.Fatal("showJson should print valid json, got", err)
+	}
+
+	if len(parsed) != 1 || parsed[0].Service != "web" {
+		t.Fatal("showJson should print every container, got", parsed)
+	}
+
+	expected := types.Port{HostIp: "127.0.0.1", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"}
+	if len(parsed[0].Ports) != 1 || parsed[0].Ports[0] != expected {
+		t.Error("showJson should include the structured ports, got", parsed[0].Ports)
+	}
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"co2/docker"
+	"co2/printer"
+	"co2/types"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchStats bool
+	groupBy    string
+
+	// How often the stats get refreshed when watching them
+	statsInterval = 2 * time.Second
+
+	statsCmd = &cobra.Command{
+		Use:   "stats",
+		Short: "Shows how many resources the carbon containers are using",
+		Args:  cobra.NoArgs,
+		Run:   execStats,
+	}
+)
+
+// Adds all the required flags
+func init() {
+	statsCmd.Flags().BoolVarP(&watchStats, "watch", "w", false, "keep refreshing the stats until stopped")
+	statsCmd.Flags().StringVarP(&groupBy, "group", "g", "store", "what to group the containers by, either `store` or `compose`")
+}
+
+// Shows the resource usage of all running carbon containers,
+// once, or over and over again if we're watching.
+func execStats(cmd *cobra.Command, args []string) {
+	if groupBy != "store" && groupBy != "compose" {
+		printer.Error("ERROR", "can't group by", groupBy)
+		printer.Extra(printer.Red, "Use either `store` or `compose`")
+		return
+	}
+
+	for {
+		containers := docker.RunningContainers(types.LabelService)
+		stats := sample(containers)
+
+		if watchStats {
+			// Clear the screen so the tables stay in place
+			printer.Ln("\033[H\033[2J")
+		}
+
+		if len(containers) == 0 {
+			printer.Info(printer.Cyan, "STATS", "No running carbon containers", "")
+		}
+
+		groups, keys := groupContainers(containers, groupBy)
+		for _, key := range keys {
+			printer.Info(printer.Cyan, "STATS", groupBy+":", key)
+
+			table := statsTable(groups[key], stats)
+			table.Display()
+		}
+
+		if !watchStats {
+			return
+		}
+
+		time.Sleep(statsInterval)
+	}
+}
+
+// Samples the resource usage of all the given containers at once
+// since docker takes a moment to measure each one of them.
+//
+// Containers that can't be sampled, because they stopped in the
+// meantime for example, are left out.
+func sample(containers []types.Container) map[string]types.ContainerStats {
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	stats := map[string]types.ContainerStats{}
+
+	for _, container := range containers {
+		wg.Add(1)
+
+		go func(name string) {
+			defer wg.Done()
+
+			sampled, err := docker.Stats(name)
+			if err != nil {
+				return
+			}
+
+			mutex.Lock()
+			stats[name] = sampled
+			mutex.Unlock()
+		}(container.Name)
+	}
+
+	wg.Wait()
+	return stats
+}
+
+// Groups the given containers by the store they came from or by
+// the compose file they were started with, based on their labels.
+//
+// The keys of the groups are returned sorted as well so that
+// the groups are always shown in the same order.
+func groupContainers(containers []types.Container, by string) (map[string][]types.Container, []string) {
+	label := types.LabelStore
+	if by == "compose" {
+		label = types.LabelComposeFile
+	}
+
+	groups := map[string][]types.Container{}
+	keys := []string{}
+
+	for _, container := range containers {
+		key := container.Labels[label]
+		if key == "" {
+			key = "none"
+		}
+
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		groups[key] = append(groups[key], container)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		group := groups[key]
+
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].Labels[types.LabelService] < group[j].Labels[types.LabelService]
+		})
+	}
+
+	return groups, keys
+}
+
+// Generates a table of the resource usage of the given containers.
+func statsTable(containers []types.Container, stats map[string]types.ContainerStats) printer.Table {
+	table := printer.NewTable(7)
+
+	table.Header(
+		"SERVICE",
+		"NAME",
+		"CPU %",
+		"MEM USAGE / LIMIT",
+		"MEM %",
+		"NET I/O",
+		"BLOCK I/O",
+	)
+
+	for _, container := range containers {
+		sampled, ok := stats[container.Name]
+		if !ok {
+			table.Row(container.Labels[types.LabelService], container.Name, "-", "-", "-", "-", "-")
+			continue
+		}
+
+		table.Row(
+			container.Labels[types.LabelService],
+			fadedStyle.Render(container.Name),
+			fmt.Sprintf("%.2f%%", sampled.CPUPercent),
+			units.BytesSize(float64(sampled.MemoryUsage))+" / "+units.BytesSize(float64(sampled.MemoryLimit)),
+			fmt.Sprintf("%.2f%%", sampled.MemoryPercent()),
+			fadedStyle.Render(units.HumanSize(float64(sampled.NetworkRx))+" / "+units.HumanSize(float64(sampled.NetworkTx))),
+			fadedStyle.Render(units.HumanSize(float64(sampled.BlockRead))+" / "+units.HumanSize(float64(sampled.BlockWrite))),
+		)
+	}
+
+	return table
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"co2/types"
+	"strings"
+	"testing"
+
+	"github.com/4khara/replica"
+	dockerTypes "github.com/docker/docker/api/types"
+)
+
+func mockLabelledContainers() []types.Container {
+	return []types.Container{
+		{Name: "web-1", Labels: map[string]string{types.LabelService: "web", types.LabelStore: "uid2", types.LabelComposeFile: "/b.yml"}},
+		{Name: "db-1", Labels: map[string]string{types.LabelService: "db", types.LabelStore: "uid1", types.LabelComposeFile: "/b.yml"}},
+		{Name: "api-1", Labels: map[string]string{types.LabelService: "api", types.LabelStore: "uid1", types.LabelComposeFile: "/a.yml"}},
+		{Name: "loose-1", Labels: map[string]string{types.LabelService: "loose", types.LabelComposeFile: "/a.yml"}},
+	}
+}
+
+func TestGroupContainersByStore(t *testing.T) {
+	groups, keys := groupContainers(mockLabelledContainers(), "store")
+
+	if strings.Join(keys, ",") != "none,uid1,uid2" {
+		t.Error("groupContainers should return the sorted stores, got", keys)
+	}
+
+	uid1 := groups["uid1"]
+	if len(uid1) != 2 || uid1[0].Name != "api-1" || uid1[1].Name != "db-1" {
+		t.Error("groupContainers should group and sort the containers of each store, got", uid1)
+	}
+}
+
+func TestGroupContainersByComposeFile(t *testing.T) {
+	groups, keys := groupContainers(mockLabelledContainers(), "compose")
+
+	if strings.Join(keys, ",") != "/a.yml,/b.yml" {
+		t.Error("groupContainers should return the sorted compose files, got", keys)
+	}
+
+	if len(groups["/a.yml"]) != 2 || len(groups["/b.yml"]) != 2 {
+		t.Error("groupContainers should group the containers by compose file, got", groups)
+	}
+}
+
+func TestStatsTableHasOneRowPerContainer(t *testing.T) {
+	containers := mockLabelledContainers()
+	stats := map[string]types.ContainerStats{
+		"web-1": {CPUPercent: 12.5, MemoryUsage: 1024, MemoryLimit: 4096},
+	}
+
+	table := statsTable(containers, stats)
+	rows := table.Rows()[2:]
+
+	if len(rows) != len(containers) {
+		t.Fatal("statsTable should have one row per container, got", len(rows))
+	}
+
+	if !strings.Contains(rows[0], "12.50%") || !strings.Contains(rows[0], "25.00%") {
+		t.Error("statsTable should show the cpu and memory percentages, got", rows[0])
+	}
+
+	if !strings.Contains(rows[1], "-") {
+		t.Error("statsTable should show containers without stats as well, got", rows[1])
+	}
+}
+
+func TestSampleSkipsContainersThatCantBeSampled(t *testing.T) {
+	beforeCmdTest()
+
+	replica.Mocks.SetReturnValues("Stats", dockerTypes.StatsJSON{}, notFoundError{})
+
+	if stats := sample(mockLabelledContainers()); len(stats) != 0 {
+		t.Error("sample should leave out containers that couldn't be sampled, got", stats)
+	}
+}
+
+type notFoundError struct{}
+
+func (e notFoundError) Error() string { return "No such container" }
+func (e notFoundError) NotFound()     {}
@@ -14,6 +14,9 @@ var (
 func init() {
 	storeCmd.AddCommand(addCmd)
 	storeCmd.AddCommand(removeCmd)
+	storeCmd.AddCommand(updateCmd)
+	storeCmd.AddCommand(exportCmd)
+	storeCmd.AddCommand(importCmd)
 }
 
 func execStore(cmd *cobra.Command, args []string) {}
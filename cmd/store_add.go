@@ -5,19 +5,24 @@ import (
 	"co2/helpers"
 	"co2/printer"
 	"co2/types"
+	"errors"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
 
 var (
-	store string
-	id    string
-	env   string
+	store       string
+	id          string
+	envs        []string
+	description string
+	labels      []string
+	depth       int
 
 	addCmd = &cobra.Command{
 		Use:   "add",
 		Short: "Adds a new item to the store",
-		Run:   execAdd,
+		Run:   locked(execAdd),
 	}
 )
 
@@ -25,7 +30,10 @@ var (
 func init() {
 	addCmd.Flags().StringVarP(&store, "store", "s", "", "The path to the store")
 	addCmd.Flags().StringVarP(&id, "id", "i", "", "The id of the store. If left empty, it will be generated")
-	addCmd.Flags().StringVarP(&env, "env", "e", "", "The environment file to use for this store. Should a path to the .env file.")
+	addCmd.Flags().StringArrayVarP(&envs, "env", "e", []string{}, "An environment file to use for this store. Should a path to the .env file. Can be provided multiple times, in order.")
+	addCmd.Flags().StringVar(&description, "description", "", "A short description of what the store is about")
+	addCmd.Flags().StringArrayVarP(&labels, "label", "l", []string{}, "A `key=value` label for the store. Can be provided multiple times.")
+	addCmd.Flags().IntVarP(&depth, "depth", "d", 2, "How many directories deep carbon should look for carbon.yml files in this store")
 }
 
 // Registers a new carbon store
@@ -42,14 +50,38 @@ func init() {
 // own identifier.
 //
 // If a store with an identical identifier exists already, it will
-// be deleted first. Dems the rules... No duplicates.
+// be updated instead. Dems the rules... No duplicates.
 func execAdd(cmd *cobra.Command, args []string) {
-	if !shouldAddStore(store) || !shouldAddEnv(env) {
+	if !shouldAddStore(store) {
+		return
+	}
+
+	for _, env := range envs {
+		if !shouldAddEnv(env) {
+			return
+		}
+	}
+
+	parsed, err := parseLabels(labels)
+	if err != nil {
+		printer.Error("ERROR", "Invalid label:", err.Error())
 		return
 	}
 
 	id = validateId(id, store)
-	addStore(id, store, env)
+	added := addStore(types.Store{
+		Uid:         id,
+		Path:        store,
+		Envs:        envs,
+		Description: description,
+		Labels:      parsed,
+		Depth:       depth,
+		Host:        dockerHost,
+	})
+
+	if !added {
+		return
+	}
 
 	printer.Extra(
 		printer.Green,
@@ -58,7 +90,7 @@ func execAdd(cmd *cobra.Command, args []string) {
 		"Verify all your services are found with `co2 show -c`",
 	)
 
-	if env == "" {
+	if len(envs) == 0 {
 		printer.Extra(printer.Cyan, "No environment file provided. No environment variables will be available for this store.")
 	}
 }
@@ -113,27 +145,70 @@ func validateId(from string, store string) string {
 	return from
 }
 
+// Parses a list of `key=value` labels into a map.
+// A label without a value is allowed and just gets
+// an empty value, but a label without a key is not.
+func parseLabels(raw []string) (map[string]string, error) {
+	parsed := map[string]string{}
+
+	for _, label := range raw {
+		parts := strings.SplitN(label, "=", 2)
+
+		if parts[0] == "" {
+			return nil, errors.New("missing key in '" + label + "'")
+		}
+
+		value := ""
+		if len(parts) == 2 {
+			value = parts[1]
+		}
+
+		parsed[parts[0]] = value
+	}
+
+	return parsed, nil
+}
+
 // Adds a new store to the database.
 // If there's already a store with the same uid, it will
-// attempt to delete it before inserting the new one.
+// be updated with everything that's provided instead.
 //
-// This does not allow for duplicate stores with the same uid.
-func addStore(uid, path, environment string) {
-	expandedStore := helpers.ExpandPath(path)
-	expandedEnv := ""
-
-	if environment != "" {
-		expandedEnv = helpers.ExpandPath(environment)
+// All the paths of the store are expanded before it's
+// saved, so it doesn't matter where carbon runs from later.
+//
+// This does not allow for duplicate stores with the same uid,
+// or two stores with the same path. Returns whether or not the
+// store was added.
+func addStore(store types.Store) bool {
+	store.Path = helpers.ExpandPath(store.Path)
+
+	expandedEnvs := []string{}
+	for _, env := range store.Envs {
+		expandedEnvs = append(expandedEnvs, helpers.ExpandPath(env))
 	}
+	store.Envs = expandedEnvs
 
-	printer.Info(printer.Green, "ADD", "Adding store", expandedStore)
+	if taken, ok := pathTaken(store); ok {
+		printer.Error("ERROR", "Store path already registered by:", taken.Uid)
+		printer.Extra(printer.Red, "Remove it first with `co2 store remove "+taken.Uid+"`")
 
-	store := types.Store{
-		Uid:  id,
-		Path: expandedStore,
-		Env:  expandedEnv,
+		return false
 	}
 
-	database.DeleteStore(store)
+	printer.Info(printer.Green, "ADD", "Adding store", store.Path)
 	database.AddStore(store)
+
+	return true
+}
+
+// Finds another store, with a different uid, that has
+// already registered the same path as the given one.
+func pathTaken(store types.Store) (types.Store, bool) {
+	for _, existing := range database.Stores() {
+		if existing.Path == store.Path && existing.Uid != store.Uid {
+			return existing, true
+		}
+	}
+
+	return types.Store{}, false
 }
@@ -3,6 +3,7 @@ package cmd
 import (
 	"co2/database"
 	"co2/helpers"
+	"co2/types"
 	"testing"
 )
 
@@ -67,13 +68,38 @@ func TestAddStoreDoesNotDuplicateStoresWithTheSameUid(t *testing.T) {
 	beforeCmdTest()
 
 	// Add a bunch of identical stores
-	addStore("", store, "")
-	addStore("", store, "")
-	addStore("", store, "")
-	addStore("", store, "")
+	addStore(types.Store{Path: store, Depth: 2})
+	addStore(types.Store{Path: store, Depth: 2})
+	addStore(types.Store{Path: store, Depth: 2})
+	addStore(types.Store{Path: store, Depth: 2})
 
 	// Make sure there's only one store in the database
 	if len(database.Stores()) != 1 {
 		t.Error("addStore should not duplicate stores with the same path")
 	}
 }
+
+func TestParseLabelsSplitsKeysAndValues(t *testing.T) {
+	parsed, err := parseLabels([]string{"team=core", "tier=db=primary", "flag"})
+	if err != nil {
+		t.Fatal("parseLabels should not return an error, got", err)
+	}
+
+	if parsed["team"] != "core" {
+		t.Error("parseLabels should split on the equals sign, got", parsed["team"])
+	}
+
+	if parsed["tier"] != "db=primary" {
+		t.Error("parseLabels should only split on the first equals sign, got", parsed["tier"])
+	}
+
+	if value, ok := parsed["flag"]; !ok || value != "" {
+		t.Error("parseLabels should allow labels without values")
+	}
+}
+
+func TestParseLabelsRejectsMissingKeys(t *testing.T) {
+	if _, err := parseLabels([]string{"=value"}); err == nil {
+		t.Error("parseLabels should return an error when the key is missing")
+	}
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"co2/database"
+	"co2/helpers"
+	"co2/printer"
+	"co2/types"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	root string
+
+	exportCmd = &cobra.Command{
+		Use:   "export [file]",
+		Short: "Exports all registered stores into a workspace file",
+		Args:  cobra.MaximumNArgs(1),
+		Run:   execExport,
+	}
+)
+
+// Adds all the required flags
+func init() {
+	exportCmd.Flags().StringVarP(&root, "root", "r", ".", "The directory that all the exported paths should be relative to")
+}
+
+// Writes all the registered stores into a workspace file
+// so they can be shared and imported somewhere else.
+//
+// If no file is provided, `workspace.yml` in the current
+// directory will be used.
+func execExport(cmd *cobra.Command, args []string) {
+	file := "workspace.yml"
+	if len(args) > 0 {
+		file = args[0]
+	}
+
+	stores := database.Stores()
+	if len(stores) == 0 {
+		printer.Error("ERROR", "No registered stores to export", "")
+		return
+	}
+
+	workspace, err := workspaceFrom(stores, helpers.ExpandPath(root))
+	if err != nil {
+		printer.Error("ERROR", "Could not make the paths relative:", err.Error())
+		return
+	}
+
+	if err := workspace.Save(file); err != nil {
+		printer.Error("ERROR", "Could not save the workspace file:", err.Error())
+		return
+	}
+
+	printer.Info(
+		printer.Green,
+		"EXPORT",
+		fmt.Sprintf("Exported %d stores to", len(workspace.Stores)),
+		file,
+	)
+	printer.Extra(printer.Green, "Import them somewhere else with `co2 store import "+file+" --root <dir>`")
+}
+
+// Maps all the provided stores into a workspace, making sure
+// that all of the paths are relative to the given root.
+//
+// The stores are sorted by their uid so that exporting the same
+// stores twice always results in the exact same file.
+func workspaceFrom(stores []types.Store, root string) (types.Workspace, error) {
+	workspace := types.Workspace{}
+
+	for _, store := range stores {
+		path, err := relative(root, store.Path)
+		if err != nil {
+			return workspace, err
+		}
+
+		envs := []string{}
+		for _, env := range store.Envs {
+			rel, err := relative(root, env)
+			if err != nil {
+				return workspace, err
+			}
+
+			envs = append(envs, rel)
+		}
+
+		workspace.Stores = append(workspace.Stores, types.WorkspaceStore{
+			Uid:         store.Uid,
+			Path:        path,
+			Envs:        envs,
+			Description: store.Description,
+			Labels:      store.Labels,
+			Depth:       store.Depth,
+			Host:        store.Host,
+		})
+	}
+
+	sort.Slice(workspace.Stores, func(i, j int) bool {
+		return workspace.Stores[i].Uid < workspace.Stores[j].Uid
+	})
+
+	return workspace, nil
+}
+
+// Turns the given path into a path relative to the given root,
+// always with forward slashes so the file works on every platform.
+func relative(root, path string) (string, error) {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.ToSlash(rel), nil
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"co2/types"
+	"testing"
+)
+
+func TestWorkspaceFromMakesPathsRelativeToRoot(t *testing.T) {
+	stores := []types.Store{
+		{
+			Uid:   "uid1",
+			Path:  "/home/someone/code/a",
+			Envs:  []string{"/home/someone/code/a/.env"},
+			Depth: 2,
+		},
+	}
+
+	workspace, err := workspaceFrom(stores, "/home/someone/code")
+	if err != nil {
+		t.Fatal("workspaceFrom should not return an error, got", err)
+	}
+
+	if workspace.Stores[0].Path != "a" {
+		t.Errorf("workspaceFrom should make the path relative, expected 'a' got '%s'", workspace.Stores[0].Path)
+	}
+
+	if workspace.Stores[0].Envs[0] != "a/.env" {
+		t.Errorf("workspaceFrom should make the env relative, expected 'a/.env' got '%s'", workspace.Stores[0].Envs[0])
+	}
+}
+
+func TestWorkspaceFromKeepsEmptyEnvEmpty(t *testing.T) {
+	stores := []types.Store{
+		{
+			Uid:  "uid1",
+			Path: "/home/someone/code/a",
+		},
+	}
+
+	workspace, _ := workspaceFrom(stores, "/home/someone/code")
+
+	if len(workspace.Stores[0].Envs) != 0 {
+		t.Error("workspaceFrom should not make up env files when there are none, got", workspace.Stores[0].Envs)
+	}
+}
+
+func TestWorkspaceFromSortsByUid(t *testing.T) {
+	stores := []types.Store{
+		{Uid: "uid3", Path: "/code/c"},
+		{Uid: "uid1", Path: "/code/a"},
+		{Uid: "uid2", Path: "/code/b"},
+	}
+
+	workspace, _ := workspaceFrom(stores, "/code")
+
+	for i, expected := range []string{"uid1", "uid2", "uid3"} {
+		if workspace.Stores[i].Uid != expected {
+			t.Errorf("workspaceFrom should sort by uid, expected %s at %d got %s", expected, i, workspace.Stores[i].Uid)
+		}
+	}
+}
+
+func TestWorkspaceFromKeepsTheHost(t *testing.T) {
+	stores := []types.Store{
+		{Uid: "uid1", Path: "/code/a", Host: "ssh://dev@remote"},
+	}
+
+	workspace, _ := workspaceFrom(stores, "/code")
+
+	if workspace.Stores[0].Host != "ssh://dev@remote" {
+		t.Error("workspaceFrom should keep the docker host of the store, got", workspace.Stores[0].Host)
+	}
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"co2/database"
+	"co2/helpers"
+	"co2/printer"
+	"co2/types"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	overwrite bool
+
+	importCmd = &cobra.Command{
+		Use:   "import <file>",
+		Short: "Registers all the stores defined in a workspace file",
+		Args:  cobra.ExactArgs(1),
+		Run:   locked(execImport),
+	}
+)
+
+// Adds all the required flags
+func init() {
+	importCmd.Flags().StringVarP(&root, "root", "r", ".", "The directory that all the paths in the workspace are relative to")
+	importCmd.Flags().BoolVarP(&overwrite, "update", "u", false, "Update stores that are already registered instead of skipping them")
+}
+
+// Reads the provided workspace file and registers all of the
+// stores within it, resolving their paths from the given root.
+//
+// Stores with a uid that's already registered are skipped unless
+// the update flag is provided, in which case they are updated.
+func execImport(cmd *cobra.Command, args []string) {
+	workspace, err := types.LoadWorkspace(args[0])
+	if err != nil {
+		printer.Error("ERROR", "Could not read the workspace file:", err.Error())
+		return
+	}
+
+	printer.Info(
+		printer.Green,
+		"IMPORT",
+		fmt.Sprintf("Importing %d stores from", len(workspace.Stores)),
+		args[0],
+	)
+
+	stores := storesFrom(workspace, helpers.ExpandPath(root))
+	imported := importStores(stores, overwrite)
+
+	printer.Extra(
+		printer.Green,
+		fmt.Sprintf("Imported %d out of %d stores", imported, len(stores)),
+		"Use `co2 show --stores` to see all of them",
+	)
+}
+
+// Maps all the stores within a workspace back into real
+// stores, resolving all the relative paths from the given root.
+func storesFrom(workspace types.Workspace, root string) []types.Store {
+	stores := []types.Store{}
+
+	for _, ws := range workspace.Stores {
+		envs := []string{}
+		for _, env := range ws.Envs {
+			envs = append(envs, absolute(root, env))
+		}
+
+		depth := ws.Depth
+		if depth == 0 {
+			depth = 2
+		}
+
+		stores = append(stores, types.Store{
+			Uid:         ws.Uid,
+			Path:        absolute(root, ws.Path),
+			Envs:        envs,
+			Description: ws.Description,
+			Labels:      ws.Labels,
+			Depth:       depth,
+			Host:        ws.Host,
+		})
+	}
+
+	return stores
+}
+
+// Registers all the provided stores that aren't registered
+// already, or all of them if we should overwrite existing ones.
+//
+// Stores whose directory doesn't exist on this machine are skipped
+// since there would be nothing to look through anyway.
+//
+// Returns how many of the stores actually got registered.
+func importStores(stores []types.Store, overwrite bool) int {
+	existing := map[string]types.Store{}
+	for _, store := range database.Stores() {
+		existing[store.Uid] = store
+	}
+
+	imported := 0
+
+	for _, store := range stores {
+		if !helpers.Exists(store.Path) {
+			printer.Extra(printer.Yellow, "Skipping '"+store.Uid+"', directory not found: "+store.Path)
+			continue
+		}
+
+		if _, ok := existing[store.Uid]; ok && !overwrite {
+			printer.Extra(printer.Cyan, "Skipping '"+store.Uid+"', already registered. Use `--update` to replace it")
+			continue
+		}
+
+		if taken, ok := pathTaken(store); ok {
+			printer.Extra(printer.Yellow, "Skipping '"+store.Uid+"', its directory is already registered by '"+taken.Uid+"'")
+			continue
+		}
+
+		database.AddStore(store)
+		printer.Extra(printer.Green, "Imported store: "+store.Uid)
+
+		imported++
+	}
+
+	return imported
+}
+
+// Resolves the given slash separated path from the
+// given root, unless it's already absolute.
+func absolute(root, path string) string {
+	path = filepath.FromSlash(path)
+
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(root, path)
+}
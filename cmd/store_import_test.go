@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"co2/database"
+	"co2/helpers"
+	"co2/types"
+	"path/filepath"
+	"testing"
+)
+
+func TestStoresFromResolvesPathsFromRoot(t *testing.T) {
+	workspace := types.Workspace{
+		Stores: []types.WorkspaceStore{
+			{Uid: "uid1", Path: "a", Envs: []string{"a/.env"}, Depth: 3},
+		},
+	}
+
+	stores := storesFrom(workspace, "/code")
+
+	if stores[0].Path != filepath.Join("/code", "a") {
+		t.Error("storesFrom should resolve the path from the root, got", stores[0].Path)
+	}
+
+	if stores[0].Envs[0] != filepath.Join("/code", "a", ".env") {
+		t.Error("storesFrom should resolve the env from the root, got", stores[0].Envs[0])
+	}
+
+	if stores[0].Depth != 3 {
+		t.Error("storesFrom should keep the depth, got", stores[0].Depth)
+	}
+}
+
+func TestStoresFromDefaultsDepth(t *testing.T) {
+	workspace := types.Workspace{
+		Stores: []types.WorkspaceStore{
+			{Uid: "uid1", Path: "a"},
+		},
+	}
+
+	stores := storesFrom(workspace, "/code")
+
+	if stores[0].Depth != 2 {
+		t.Error("storesFrom should default the depth to 2, got", stores[0].Depth)
+	}
+}
+
+func TestImportStoresSkipsExistingUidsUnlessOverwriting(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	home := helpers.UserHomeDir()
+	database.AddStore(types.Store{Uid: "uid1", Path: "/somewhere/else", Depth: 2})
+
+	stores := []types.Store{
+		{Uid: "uid1", Path: home, Depth: 2},
+		{Uid: "uid2", Path: helpers.ComposeDir(), Depth: 2},
+	}
+
+	if imported := importStores(stores, false); imported != 1 {
+		t.Error("importStores should skip existing uids, expected 1 import got", imported)
+	}
+
+	if imported := importStores(stores, true); imported != 2 {
+		t.Error("importStores should replace existing uids when overwriting, expected 2 imports got", imported)
+	}
+
+	for _, store := range database.Stores() {
+		if store.Uid == "uid1" && store.Path != home {
+			t.Error("importStores should have updated the path of the existing store, got", store.Path)
+		}
+	}
+
+	if len(database.Stores()) != 2 {
+		t.Error("importStores should not duplicate stores, got", len(database.Stores()))
+	}
+}
+
+func TestImportStoresSkipsMissingDirectories(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	stores := []types.Store{
+		{Uid: "uid1", Path: "/this/does/not/exist/hopefully", Depth: 2},
+	}
+
+	if imported := importStores(stores, false); imported != 0 {
+		t.Error("importStores should skip stores that don't exist, got", imported)
+	}
+}
+
+func TestImportStoresSkipsPathsRegisteredByOtherStores(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	home := helpers.UserHomeDir()
+	database.AddStore(types.Store{Uid: "uid1", Path: home, Depth: 2})
+
+	stores := []types.Store{
+		{Uid: "uid2", Path: home, Depth: 2},
+	}
+
+	if imported := importStores(stores, true); imported != 0 {
+		t.Error("importStores should skip paths that are registered by other stores, got", imported)
+	}
+}
@@ -15,7 +15,7 @@ var (
 		Use:   "remove",
 		Short: "Removes a store with the given ID",
 		Args:  cobra.MinimumNArgs(1),
-		Run:   execRemove,
+		Run:   locked(execRemove),
 	}
 )
 
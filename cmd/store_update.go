@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"co2/database"
+	"co2/helpers"
+	"co2/printer"
+	"co2/types"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	addEnvs    []string
+	removeEnvs []string
+	unlabels   []string
+
+	updateCmd = &cobra.Command{
+		Use:   "update <id>",
+		Short: "Updates the environment files and metadata of a store",
+		Args:  cobra.ExactArgs(1),
+		Run:   locked(execUpdate),
+	}
+)
+
+// Adds all the required flags
+func init() {
+	updateCmd.Flags().StringArrayVar(&addEnvs, "add-env", []string{}, "An environment file to append to the store. Can be provided multiple times.")
+	updateCmd.Flags().StringArrayVar(&removeEnvs, "remove-env", []string{}, "An environment file to remove from the store. Can be provided multiple times.")
+	updateCmd.Flags().StringArrayVarP(&labels, "label", "l", []string{}, "A `key=value` label to set on the store. Can be provided multiple times.")
+	updateCmd.Flags().StringArrayVar(&unlabels, "unlabel", []string{}, "The key of a label to remove from the store. Can be provided multiple times.")
+	updateCmd.Flags().StringVar(&description, "description", "", "A short description of what the store is about")
+}
+
+// Updates the store with the provided id.
+//
+// Environment files are appended to the end of the list so
+// they override everything that came before them, and removed
+// from wherever they are.
+//
+// The description is only touched if the flag is actually
+// provided, so it can also be cleared with an empty string. The
+// same goes for the docker host of the store.
+func execUpdate(cmd *cobra.Command, args []string) {
+	uid := args[0]
+
+	found, ok := storeByUid(uid)
+	if !ok {
+		printer.Error("ERROR", "No store with the id:", uid)
+		printer.Extra(printer.Red, "Use `co2 show --stores` to see all id's")
+		return
+	}
+
+	for _, env := range addEnvs {
+		if !shouldAddEnv(env) {
+			return
+		}
+	}
+
+	parsed, err := parseLabels(labels)
+	if err != nil {
+		printer.Error("ERROR", "Invalid label:", err.Error())
+		return
+	}
+
+	printer.Info(printer.Green, "UPDATE", "Updating store", uid)
+
+	updated := updateStore(found, addEnvs, removeEnvs, parsed, unlabels)
+	if cmd.Flags().Changed("description") {
+		updated.Description = description
+	}
+
+	if cmd.Flags().Changed("host") || cmd.Flags().Changed("context") {
+		updated.Host = dockerHost
+	}
+
+	database.UpdateStore(updated)
+	printer.Extra(printer.Green, "Updated store: "+uid)
+}
+
+// Finds the registered store with the given uid.
+func storeByUid(uid string) (types.Store, bool) {
+	for _, store := range database.Stores() {
+		if store.Uid == uid {
+			return store, true
+		}
+	}
+
+	return types.Store{}, false
+}
+
+// Applies all the provided changes to a copy of the given
+// store and returns it. Nothing is saved here.
+//
+// Environment files that are already linked to the store are
+// never added twice, and all of them are expanded first so that
+// relative paths match the stored ones.
+func updateStore(store types.Store, add, remove []string, set map[string]string, unset []string) types.Store {
+	envs := []string{}
+	removed := []string{}
+
+	for _, env := range remove {
+		removed = append(removed, helpers.ExpandPath(env))
+	}
+
+	for _, env := range store.Envs {
+		if !helpers.Contains(removed, env) {
+			envs = append(envs, env)
+		}
+	}
+
+	for _, env := range add {
+		expanded := helpers.ExpandPath(env)
+
+		if !helpers.Contains(envs, expanded) {
+			envs = append(envs, expanded)
+		}
+	}
+
+	labels := map[string]string{}
+	for key, value := range store.Labels {
+		labels[key] = value
+	}
+
+	for key, value := range set {
+		labels[key] = value
+	}
+
+	for _, key := range unset {
+		delete(labels, key)
+	}
+
+	store.Envs = envs
+	store.Labels = labels
+
+	return store
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"co2/database"
+	"co2/types"
+	"testing"
+)
+
+func TestUpdateStoreAppendsAndRemovesEnvs(t *testing.T) {
+	store := types.Store{
+		Envs: []string{"/code/.env", "/code/.env.local"},
+	}
+
+	updated := updateStore(store, []string{"/code/.env.test"}, []string{"/code/.env"}, nil, nil)
+	expected := []string{"/code/.env.local", "/code/.env.test"}
+
+	if len(updated.Envs) != len(expected) {
+		t.Fatalf("updateStore should end up with %d env files, got %d", len(expected), len(updated.Envs))
+	}
+
+	for i := range expected {
+		if updated.Envs[i] != expected[i] {
+			t.Errorf("updateStore should keep the env files in order, expected %s at %d got %s", expected[i], i, updated.Envs[i])
+		}
+	}
+}
+
+func TestUpdateStoreDoesNotDuplicateEnvs(t *testing.T) {
+	store := types.Store{
+		Envs: []string{"/code/.env"},
+	}
+
+	updated := updateStore(store, []string{"/code/.env"}, nil, nil, nil)
+
+	if len(updated.Envs) != 1 {
+		t.Error("updateStore should not add the same env file twice, got", updated.Envs)
+	}
+}
+
+func TestUpdateStoreSetsAndRemovesLabels(t *testing.T) {
+	store := types.Store{
+		Labels: map[string]string{"team": "core", "tier": "db"},
+	}
+
+	updated := updateStore(store, nil, nil, map[string]string{"team": "platform"}, []string{"tier"})
+
+	if updated.Labels["team"] != "platform" {
+		t.Error("updateStore should overwrite existing labels, got", updated.Labels["team"])
+	}
+
+	if _, ok := updated.Labels["tier"]; ok {
+		t.Error("updateStore should remove unset labels")
+	}
+
+	if store.Labels["team"] != "core" {
+		t.Error("updateStore should not touch the labels of the original store")
+	}
+}
+
+func TestUpdatedStoresKeepTheirMetadataInTheDatabase(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	database.AddStore(types.Store{Uid: "uid1", Path: "/code", Envs: []string{"/code/.env"}})
+
+	found, ok := storeByUid("uid1")
+	if !ok {
+		t.Fatal("storeByUid should find the added store")
+	}
+
+	updated := updateStore(found, []string{"/code/.env.local"}, nil, map[string]string{"team": "core"}, nil)
+	updated.Description = "All the code"
+	database.UpdateStore(updated)
+
+	found, _ = storeByUid("uid1")
+
+	if len(found.Envs) != 2 || found.Envs[1] != "/code/.env.local" {
+		t.Error("the updated env files should be saved in order, got", found.Envs)
+	}
+
+	if found.Labels["team"] != "core" {
+		t.Error("the updated labels should be saved, got", found.Labels)
+	}
+
+	if found.Description != "All the code" {
+		t.Error("the updated description should be saved, got", found.Description)
+	}
+}
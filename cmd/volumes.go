@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"co2/docker"
+	"co2/printer"
+	"co2/types"
+
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+)
+
+var (
+	volumesCmd = &cobra.Command{
+		Use:   "volumes",
+		Short: "Shows the volumes the carbon containers use",
+		Args:  cobra.NoArgs,
+		Run:   execVolumes,
+	}
+)
+
+// Registers all subcommands
+func init() {
+	volumesCmd.AddCommand(volumesPruneCmd)
+	volumesCmd.AddCommand(volumesBackupCmd)
+	volumesCmd.AddCommand(volumesRestoreCmd)
+}
+
+// Lists every volume, named or anonymous, that's mounted into
+// a carbon container, along with the service it belongs to.
+func execVolumes(cmd *cobra.Command, args []string) {
+	volumes, err := docker.Volumes(types.LabelService)
+	if err != nil {
+		printer.Error("ERROR", "couldn't list the volumes:", err.Error())
+		return
+	}
+
+	if len(volumes) == 0 {
+		printer.Info(printer.Cyan, "VOLUMES", "No carbon container uses a volume", "")
+		return
+	}
+
+	table := volumesTable(volumes)
+	table.Display()
+}
+
+// Generates a table of the given volumes.
+//
+// Anonymous volumes only have a long hash for a name, which
+// is shortened the same way docker shortens container IDs.
+func volumesTable(volumes []types.Volume) printer.Table {
+	table := printer.NewTable(6)
+
+	table.Header(
+		"SERVICE",
+		"VOLUME",
+		"DESTINATION",
+		"SIZE",
+		"CONTAINER",
+		"STATUS",
+	)
+
+	for _, volume := range volumes {
+		name := volume.Name
+		if volume.Anonymous {
+			name = fadedStyle.Render(name[:12] + " (anonymous)")
+		}
+
+		size := "-"
+		if volume.Size >= 0 {
+			size = units.HumanSize(float64(volume.Size))
+		}
+
+		status := "running"
+		if !volume.Running {
+			status = staleStyle.Render("stopped")
+		}
+
+		table.Row(
+			volume.Service,
+			name,
+			volume.Destination,
+			size,
+			fadedStyle.Render(volume.Container),
+			status,
+		)
+	}
+
+	return table
+}
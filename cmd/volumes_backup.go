@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"co2/docker"
+	"co2/printer"
+	"co2/types"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	pickedVolume string
+
+	volumesBackupCmd = &cobra.Command{
+		Use:   "backup <service> <file.tar>",
+		Short: "Backs up a volume of the given service into a tar archive",
+		Args:  cobra.ExactArgs(2),
+		Run:   execVolumesBackup,
+	}
+
+	volumesRestoreCmd = &cobra.Command{
+		Use:   "restore <service> <file.tar>",
+		Short: "Restores a volume of the given service from a tar archive",
+		Args:  cobra.ExactArgs(2),
+		Run:   locked(execVolumesRestore),
+	}
+)
+
+// Adds the required flags
+func init() {
+	help := "the volume to use, by name or destination, when the service has more than one"
+	volumesBackupCmd.Flags().StringVarP(&pickedVolume, "volume", "v", "", help)
+	volumesRestoreCmd.Flags().StringVarP(&pickedVolume, "volume", "v", "", help)
+}
+
+// Writes everything within a volume of the given service
+// into a tar archive, so it can be restored later.
+//
+// Backing up the volume of a running service works, but it
+// might not be consistent if the service is writing to it.
+func execVolumesBackup(cmd *cobra.Command, args []string) {
+	volume, ok := pickVolume(args[0])
+	if !ok {
+		os.Exit(1)
+	}
+
+	if volume.Running {
+		printer.Extra(printer.Yellow, "'"+args[0]+"' is running, the backup might catch it halfway through a write")
+	}
+
+	printer.Info(printer.Green, "BACKUP", "Backing up "+volume.Name+" into", args[1])
+
+	if err := docker.BackupVolume(volume.Name, args[1]); err != nil {
+		printer.Error("ERROR", "couldn't back up the volume:", err.Error())
+		os.Exit(1)
+	}
+
+	printer.Extra(printer.Green, "Done")
+}
+
+// Replaces everything within a volume of the given service with
+// what's in the given archive. The service has to be stopped first
+// so nothing's using the volume while it's being replaced.
+func execVolumesRestore(cmd *cobra.Command, args []string) {
+	volume, ok := pickVolume(args[0])
+	if !ok {
+		os.Exit(1)
+	}
+
+	if volume.Running {
+		printer.Error("ERROR", "the volume is in use by:", volume.Container)
+		printer.Extra(printer.Red, "Stop '"+args[0]+"' first")
+		os.Exit(1)
+	}
+
+	printer.Info(printer.Green, "RESTORE", "Restoring "+volume.Name+" from", args[1])
+
+	if err := docker.RestoreVolume(volume.Name, args[1]); err != nil {
+		printer.Error("ERROR", "couldn't restore the volume:", err.Error())
+		os.Exit(1)
+	}
+
+	printer.Extra(printer.Green, "Done")
+}
+
+// Finds the volume of the given service that's being asked for,
+// describing what went wrong if there isn't exactly one.
+func pickVolume(service string) (types.Volume, bool) {
+	volumes, err := docker.Volumes(types.LabelService)
+	if err != nil {
+		printer.Error("ERROR", "couldn't list the volumes:", err.Error())
+		return types.Volume{}, false
+	}
+
+	volume, err := serviceVolume(volumes, service, pickedVolume)
+	if err != nil {
+		printer.Error("ERROR", "'"+service+"' "+err.Error(), "")
+		return types.Volume{}, false
+	}
+
+	return volume, true
+}
+
+// Picks a single volume of the given service, either the one
+// that's asked for, by name or destination, or the only one there is.
+//
+// Volumes can be shared with other services, so if any container
+// using the volume is running, the returned one is as well.
+func serviceVolume(volumes []types.Volume, service string, pick string) (types.Volume, error) {
+	running := map[string]bool{}
+	for _, volume := range volumes {
+		running[volume.Name] = running[volume.Name] || volume.Running
+	}
+
+	found := []types.Volume{}
+
+	for _, volume := range volumes {
+		if volume.Service != service {
+			continue
+		}
+
+		if pick != "" && volume.Name != pick && volume.Destination != pick {
+			continue
+		}
+
+		duplicate := false
+		for _, existing := range found {
+			duplicate = duplicate || existing.Name == volume.Name
+		}
+
+		if !duplicate {
+			volume.Running = running[volume.Name]
+			found = append(found, volume)
+		}
+	}
+
+	if len(found) == 0 {
+		return types.Volume{}, errors.New("has no such volume")
+	}
+
+	if len(found) > 1 {
+		destinations := []string{}
+		for _, volume := range found {
+			destinations = append(destinations, volume.Destination)
+		}
+
+		return types.Volume{}, fmt.Errorf("has more than one volume, pick one of %s with `--volume`", strings.Join(destinations, ", "))
+	}
+
+	return found[0], nil
+}
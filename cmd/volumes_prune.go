@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"co2/database"
+	"co2/docker"
+	"co2/helpers"
+	"co2/printer"
+	"co2/types"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	pruneForce bool
+
+	volumesPruneCmd = &cobra.Command{
+		Use:   "prune",
+		Short: "Removes the volumes of stopped carbon services",
+		Args:  cobra.NoArgs,
+		Run:   locked(execVolumesPrune),
+	}
+)
+
+// Adds the required flags
+func init() {
+	volumesPruneCmd.Flags().BoolVarP(&pruneForce, "force", "f", false, "actually remove the volumes instead of only listing them")
+}
+
+// Removes the volumes of every carbon service that was stopped,
+// along with the stopped containers that are holding on to them,
+// since docker doesn't let go of a volume while a container has it.
+//
+// Nothing gets removed unless forced to, so it's always
+// possible to look at what would be gone first.
+func execVolumesPrune(cmd *cobra.Command, args []string) {
+	volumes, err := docker.Volumes(types.LabelService)
+	if err != nil {
+		printer.Error("ERROR", "couldn't list the volumes:", err.Error())
+		return
+	}
+
+	known := []string{}
+	for _, container := range database.Containers() {
+		known = append(known, container.Name)
+	}
+
+	containers, names := prunable(volumes, known)
+	if len(names) == 0 {
+		printer.Info(printer.Cyan, "PRUNE", "No volumes of stopped services", "")
+		return
+	}
+
+	printer.Info(printer.Green, "PRUNE", "Volumes of stopped services:", "")
+	printer.Extra(printer.Grey, names...)
+
+	if !pruneForce {
+		printer.Extra(printer.Cyan, "Run again with `--force` to remove them")
+		return
+	}
+
+	failed := false
+
+	for _, container := range containers {
+		if err := docker.Remove(container, false); err != nil {
+			printer.Error("ERROR", "couldn't remove "+container+":", err.Error())
+			failed = true
+		}
+	}
+
+	for _, name := range names {
+		if err := docker.RemoveVolume(name); err != nil {
+			printer.Error("ERROR", "couldn't remove "+name+":", err.Error())
+			failed = true
+			continue
+		}
+
+		printer.Extra(printer.Green, "Removed "+name)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// Works out which of the given volumes only belong to stopped
+// services, and which containers have to go for them to be removed.
+//
+// A container only counts as stopped if carbon doesn't think it's
+// running either, so ones that crashed are never pruned without
+// being stopped first.
+func prunable(volumes []types.Volume, known []string) ([]string, []string) {
+	used := map[string]bool{}
+	for _, volume := range volumes {
+		if volume.Running || helpers.Contains(known, volume.Container) {
+			used[volume.Name] = true
+		}
+	}
+
+	containers := []string{}
+	names := []string{}
+
+	for _, volume := range volumes {
+		if used[volume.Name] {
+			continue
+		}
+
+		if !helpers.Contains(containers, volume.Container) {
+			containers = append(containers, volume.Container)
+		}
+
+		if !helpers.Contains(names, volume.Name) {
+			names = append(names, volume.Name)
+		}
+	}
+
+	sort.Strings(containers)
+	sort.Strings(names)
+
+	return containers, names
+}
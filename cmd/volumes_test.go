@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"co2/types"
+	"strings"
+	"testing"
+)
+
+func mockVolumes() []types.Volume {
+	return []types.Volume{
+		{Name: "pgdata", Destination: "/var/lib/postgresql/data", Size: 2048, Container: "db-1", Service: "db"},
+		{Name: "pgconf", Destination: "/etc/postgresql", Size: -1, Container: "db-1", Service: "db"},
+		{Name: "shared", Destination: "/shared", Container: "db-1", Service: "db"},
+		{Name: "shared", Destination: "/shared", Container: "api-1", Service: "api", Running: true},
+		{Name: strings.Repeat("ab", 32), Destination: "/cache", Anonymous: true, Container: "worker-1", Service: "worker"},
+	}
+}
+
+func TestVolumesTableShortensAnonymousVolumes(t *testing.T) {
+	table := volumesTable(mockVolumes())
+	rows := table.Rows()[2:]
+
+	if len(rows) != 5 {
+		t.Fatal("volumesTable should have one row per volume, got", len(rows))
+	}
+
+	if !strings.Contains(rows[0], "2.048kB") || !strings.Contains(rows[0], "stopped") {
+		t.Error("volumesTable should show the size and status, got", rows[0])
+	}
+
+	if !strings.Contains(rows[4], "abababababab (anonymous)") || strings.Contains(rows[4], strings.Repeat("ab", 32)) {
+		t.Error("volumesTable should shorten anonymous volumes, got", rows[4])
+	}
+}
+
+func TestPrunableOnlyTakesVolumesOfStoppedServices(t *testing.T) {
+	containers, names := prunable(mockVolumes(), []string{"worker-1"})
+
+	if strings.Join(names, ",") != "pgconf,pgdata" {
+		t.Error("prunable should leave out volumes that are still used, got", names)
+	}
+
+	if strings.Join(containers, ",") != "db-1" {
+		t.Error("prunable should return the containers holding the volumes, got", containers)
+	}
+}
+
+func TestServiceVolumePicksASingleVolume(t *testing.T) {
+	volumes := mockVolumes()
+
+	if _, err := serviceVolume(volumes, "db", ""); err == nil || !strings.Contains(err.Error(), "/etc/postgresql") {
+		t.Error("serviceVolume should ask to pick between several volumes, got", err)
+	}
+
+	picked, err := serviceVolume(volumes, "db", "/var/lib/postgresql/data")
+	if err != nil || picked.Name != "pgdata" {
+		t.Error("serviceVolume should pick by destination, got", picked, err)
+	}
+
+	shared, err := serviceVolume(volumes, "db", "shared")
+	if err != nil || !shared.Running {
+		t.Error("serviceVolume should know the volume is in use by another service, got", shared, err)
+	}
+
+	if _, err := serviceVolume(volumes, "db", "nope"); err == nil {
+		t.Error("serviceVolume should fail for volumes that don't exist")
+	}
+}
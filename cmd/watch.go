@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"co2/database"
+	"co2/docker"
+	"co2/printer"
+	"co2/runner"
+	"co2/types"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Everything that can happen to a container that's
+	// worth knowing about while it's running.
+	watchedActions = []string{"start", "die", "oom", "health_status"}
+
+	watchCmd = &cobra.Command{
+		Use:   "watch",
+		Short: "Shows what happens to carbon containers as it happens",
+		Args:  cobra.NoArgs,
+		Run:   execWatch,
+	}
+)
+
+// Subscribes to the docker daemon and shows everything that
+// happens to the containers that carbon started, until the
+// user stops it or the connection to docker breaks.
+//
+// This doesn't take the lock since it never stops running, and
+// every status update it makes is small enough to not get in the
+// way of anything else.
+func execWatch(cmd *cobra.Command, args []string) {
+	printer.Info(printer.Cyan, "WATCH", "Watching all carbon containers", "ctrl+c to stop")
+
+	events, errs := docker.Events(watchedActions, types.LabelService)
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			watched(event)
+		case err := <-errs:
+			printer.Error("ERROR", "lost the connection to docker:", err.Error())
+			os.Exit(1)
+		}
+	}
+}
+
+// Shows a single event, labelled the same way as the logs of
+// the container it belongs to, and makes sure the database knows
+// about the new status of the container.
+func watched(event types.ContainerEvent) {
+	status, message, color := describe(event)
+
+	label := runner.Label(types.Command{
+		Text:  event.Name,
+		Label: event.Name,
+	})
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color(color))
+
+	printer.Ln(
+		label+fadedStyle.Render(event.Time.Format("15:04:05")),
+		style.Render(message),
+	)
+
+	database.SetContainerStatus(event.Name, status)
+}
+
+// Figures out what the status of the container is after the
+// given event, and how to describe what happened to the user.
+func describe(event types.ContainerEvent) (string, string, printer.Color) {
+	switch event.Action {
+	case "start":
+		return "Up", "started as " + event.Name, printer.Green
+	case "die":
+		return fmt.Sprintf("Exited (%s)", event.ExitCode), "exited with code " + event.ExitCode, printer.Red
+	case "oom":
+		return "Out of memory", "ran out of memory", printer.Red
+	case "health_status":
+		color := printer.Green
+		if event.Health != "healthy" {
+			color = printer.Yellow
+		}
+
+		return fmt.Sprintf("Up (%s)", event.Health), "is " + event.Health, color
+	}
+
+	return event.Action, event.Action, printer.Grey
+}
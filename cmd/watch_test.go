@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"co2/database"
+	"co2/types"
+	"testing"
+
+	"github.com/4khara/replica"
+	"github.com/docker/docker/api/types/events"
+)
+
+func TestWatchedUpdatesTheStatusOfTheContainer(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	database.AddContainer(types.Container{Name: "api-abcdefghij", Status: "Up"})
+
+	watched(types.ContainerEvent{
+		Action:   "die",
+		ExitCode: "137",
+		Name:     "api-abcdefghij",
+		Labels:   map[string]string{types.LabelService: "api"},
+	})
+
+	if status := database.Containers()[0].Status; status != "Exited (137)" {
+		t.Error("watched should update the status of the container, got", status)
+	}
+
+	if replica.Mocks.GetCallCount("Ln") != 1 {
+		t.Error("watched should print the event")
+	}
+}
+
+func TestDescribeUnderstandsHealthChecks(t *testing.T) {
+	status, message, _ := describe(types.ContainerEvent{Action: "health_status", Health: "unhealthy"})
+
+	if status != "Up (unhealthy)" || message != "is unhealthy" {
+		t.Error("describe should show the health of the container, got", status, message)
+	}
+}
+
+func TestExecWatchHandlesEveryEventUntilTheStreamEnds(t *testing.T) {
+	beforeCmdTest()
+	defer afterCmdTest()
+
+	database.AddContainer(types.Container{Name: "api-abcdefghij", Status: "Created"})
+
+	replica.Mocks.SetReturnValues("Events", []events.Message{
+		{
+			Action: "start",
+			Actor: events.Actor{
+				ID:         "abcd",
+				Attributes: map[string]string{"name": "api-abcdefghij", types.LabelService: "api"},
+			},
+		},
+		{
+			Action: "health_status: healthy",
+			Actor: events.Actor{
+				ID:         "abcd",
+				Attributes: map[string]string{"name": "api-abcdefghij", types.LabelService: "api"},
+			},
+		},
+	})
+
+	execWatch(nil, nil)
+
+	if status := database.Containers()[0].Status; status != "Up (healthy)" {
+		t.Error("execWatch should apply every event in order, got", status)
+	}
+}
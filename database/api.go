@@ -2,19 +2,31 @@ package database
 
 import (
 	"co2/types"
+	"database/sql"
+	"strings"
 )
 
 // Gets all the containers currently registered in the database
 // and maps them to our own custom Container structure.
+//
+// This includes the ports of each of the containers, and the
+// uid of the store they were started from.
 func Containers() []types.Container {
 	db, _ := Get()
 
-	rows, err := db.Query("SELECT * FROM containers;")
+	rows, err := db.Query(`
+		SELECT c.id, c.docker_uid, c.uid, c.name, c.image, c.service_name, c.compose_file,
+			c.carbon_file, c.start_args, c.status, c.store_id, COALESCE(s.uid, ''), c.host, c.created_at
+		FROM containers c
+		LEFT JOIN stores s ON s.id = c.store_id;
+	`)
 	handle(err)
 
 	var containers []types.Container
 	for rows.Next() {
 		var out types.Container
+		var storeId sql.NullInt64
+		var args string
 
 		err = rows.Scan(
 			&out.Id,
@@ -24,95 +36,369 @@ func Containers() []types.Container {
 			&out.Image,
 			&out.ServiceName,
 			&out.ComposeFile,
-			&out.Ports,
+			&out.CarbonFile,
+			&args,
 			&out.Status,
+			&storeId,
+			&out.StoreUid,
+			&out.Host,
 			&out.CreatedAt,
 		)
 		handle(err)
 
+		out.StoreId = storeId.Int64
+		out.StartArgs = strings.Fields(args)
 		containers = append(containers, out)
 	}
 
+	ports := containerPorts()
+
+	for i := range containers {
+		containers[i].Ports = ports[containers[i].Id]
+	}
+
 	return containers
 }
 
+// Gets all the containers that have published something
+// on the same host port as the given port.
+func ContainersOnPort(port types.Port) []types.Container {
+	found := []types.Container{}
+
+	for _, container := range Containers() {
+		for _, existing := range container.Ports {
+			if existing.Conflicts(port) {
+				found = append(found, container)
+				break
+			}
+		}
+	}
+
+	return found
+}
+
+// Gets all the ports of all the containers, mapped by
+// the id of the container they belong to.
+func containerPorts() map[int64]types.Ports {
+	db, _ := Get()
+
+	rows, err := db.Query("SELECT container_id, host_ip, host_port, container_port, protocol FROM container_ports ORDER BY id;")
+	handle(err)
+
+	ports := map[int64]types.Ports{}
+	for rows.Next() {
+		var id int64
+		var port types.Port
+
+		err = rows.Scan(&id, &port.HostIp, &port.HostPort, &port.ContainerPort, &port.Protocol)
+		handle(err)
+
+		ports[id] = append(ports[id], port)
+	}
+
+	return ports
+}
+
 // Gets all the stores currently registered in the database
 // and maps them to our own custom Store structure.
+//
+// This includes all the environment files and labels that
+// belong to each of the stores.
 func Stores() []types.Store {
 	db, _ := Get()
 
-	rows, err := db.Query("SELECT * FROM stores;")
+	rows, err := db.Query("SELECT id, uid, path, description, depth, host, created_at FROM stores;")
 	handle(err)
 
 	var stores []types.Store
 	for rows.Next() {
 		var out types.Store
 
-		err = rows.Scan(&out.Id, &out.Uid, &out.Path, &out.Env, &out.CreatedAt)
+		err = rows.Scan(&out.Id, &out.Uid, &out.Path, &out.Description, &out.Depth, &out.Host, &out.CreatedAt)
 		handle(err)
 
 		stores = append(stores, out)
 	}
 
+	envs := storeEnvs()
+	labels := storeLabels()
+
+	for i := range stores {
+		stores[i].Envs = envs[stores[i].Id]
+		stores[i].Labels = labels[stores[i].Id]
+	}
+
 	return stores
 }
 
-// Adds a new container to the database.
-// And updates the ID of the provided container to match the
-// inserted one.
-func AddContainer(container types.Container) types.Container {
+// Gets all the environment files of all the stores, in the
+// order they were defined, mapped by the id of the store they
+// belong to.
+func storeEnvs() map[int64][]string {
 	db, _ := Get()
 
-	stmt, err := db.Prepare("INSERT INTO containers(docker_uid, uid, name, image, service_name, compose_file, ports, status) VALUES(?,?,?,?,?,?,?,?);")
+	rows, err := db.Query("SELECT store_id, path FROM store_env_files ORDER BY store_id, position;")
 	handle(err)
 
-	res, err := stmt.Exec(
-		container.DockerUid,
-		container.Uid,
-		container.Name,
-		container.Image,
-		container.ServiceName,
-		container.ComposeFile,
-		container.Ports,
-		container.Status,
-	)
+	envs := map[int64][]string{}
+	for rows.Next() {
+		var id int64
+		var path string
+
+		err = rows.Scan(&id, &path)
+		handle(err)
+
+		envs[id] = append(envs[id], path)
+	}
+
+	return envs
+}
+
+// Gets all the labels of all the stores, mapped by the id
+// of the store they belong to.
+func storeLabels() map[int64]map[string]string {
+	db, _ := Get()
+
+	rows, err := db.Query("SELECT store_id, key, value FROM store_labels;")
 	handle(err)
 
-	id, err := res.LastInsertId()
+	labels := map[int64]map[string]string{}
+	for rows.Next() {
+		var id int64
+		var key, value string
+
+		err = rows.Scan(&id, &key, &value)
+		handle(err)
+
+		if labels[id] == nil {
+			labels[id] = map[string]string{}
+		}
+
+		labels[id][key] = value
+	}
+
+	return labels
+}
+
+// Adds a new container to the database, or updates the
+// existing one with the same name since container names
+// are always unique.
+//
+// All the ports of the container are saved along with it,
+// replacing any existing ones.
+//
+// Updates the ID of the provided container to match the
+// inserted (or updated) one.
+func AddContainer(container types.Container) types.Container {
+	db, _ := Get()
+
+	tx, err := db.Begin()
 	handle(err)
+	defer tx.Rollback()
+
+	err = tx.QueryRow("SELECT id FROM containers WHERE name=?;", container.Name).Scan(&container.Id)
+	if err != nil && err != sql.ErrNoRows {
+		handle(err)
+	}
+
+	if err == sql.ErrNoRows {
+		res, err := tx.Exec(
+			"INSERT INTO containers(docker_uid, uid, name, image, service_name, compose_file, carbon_file, start_args, status, store_id, host) VALUES(?,?,?,?,?,?,?,?,?,?,?);",
+			container.DockerUid,
+			container.Uid,
+			container.Name,
+			container.Image,
+			container.ServiceName,
+			container.ComposeFile,
+			container.CarbonFile,
+			strings.Join(container.StartArgs, " "),
+			container.Status,
+			nullable(container.StoreId),
+			container.Host,
+		)
+		handle(err)
+
+		container.Id, err = res.LastInsertId()
+		handle(err)
+	} else {
+		_, err = tx.Exec(
+			"UPDATE containers SET docker_uid=?, uid=?, image=?, service_name=?, compose_file=?, carbon_file=?, start_args=?, status=?, store_id=?, host=? WHERE id=?;",
+			container.DockerUid,
+			container.Uid,
+			container.Image,
+			container.ServiceName,
+			container.ComposeFile,
+			container.CarbonFile,
+			strings.Join(container.StartArgs, " "),
+			container.Status,
+			nullable(container.StoreId),
+			container.Host,
+			container.Id,
+		)
+		handle(err)
 
-	container.Id = id
+		_, err = tx.Exec("DELETE FROM container_ports WHERE container_id=?;", container.Id)
+		handle(err)
+	}
+
+	for _, port := range container.Ports {
+		_, err = tx.Exec(
+			"INSERT INTO container_ports(container_id, host_ip, host_port, container_port, protocol) VALUES(?,?,?,?,?);",
+			container.Id,
+			port.HostIp,
+			port.HostPort,
+			port.ContainerPort,
+			port.Protocol,
+		)
+		handle(err)
+	}
+
+	handle(tx.Commit())
 	return container
 }
 
-// Adds a new store to the database.
-// And updates the ID of the provided store to match the
-// inserted one.
+// Adds a new store to the database, or updates the existing
+// one with the same uid. Updating keeps the original ID and
+// creation date of the store.
+//
+// Updates the ID of the provided store to match the
+// inserted (or updated) one.
+//
+// All the environment files and labels of the store are
+// saved along with it, replacing any existing ones.
 func AddStore(store types.Store) types.Store {
 	db, _ := Get()
 
-	stmt, err := db.Prepare("INSERT INTO stores(uid, path, env) VALUES(?,?,?);")
+	tx, err := db.Begin()
 	handle(err)
+	defer tx.Rollback()
+
+	err = tx.QueryRow("SELECT id FROM stores WHERE uid=?;", store.Uid).Scan(&store.Id)
+	if err != nil && err != sql.ErrNoRows {
+		handle(err)
+	}
+
+	if err == sql.ErrNoRows {
+		res, err := tx.Exec(
+			"INSERT INTO stores(uid, path, description, depth, host) VALUES(?,?,?,?,?);",
+			store.Uid,
+			store.Path,
+			store.Description,
+			store.Depth,
+			store.Host,
+		)
+		handle(err)
+
+		store.Id, err = res.LastInsertId()
+		handle(err)
+	} else {
+		updateStore(tx, store)
+	}
+
+	insertStoreMetadata(tx, store)
+
+	handle(tx.Commit())
+	return store
+}
+
+// Updates everything about the store with the same uid
+// as the provided one, replacing all of its environment files
+// and labels with the provided ones.
+//
+// Returns the number of updated stores.
+func UpdateStore(store types.Store) int64 {
+	db, _ := Get()
 
-	res, err := stmt.Exec(store.Uid, store.Path, store.Env)
+	tx, err := db.Begin()
 	handle(err)
+	defer tx.Rollback()
 
-	id, err := res.LastInsertId()
+	err = tx.QueryRow("SELECT id FROM stores WHERE uid=?;", store.Uid).Scan(&store.Id)
+	if err == sql.ErrNoRows {
+		return 0
+	}
 	handle(err)
 
-	store.Id = id
-	return store
+	updateStore(tx, store)
+	insertStoreMetadata(tx, store)
+
+	handle(tx.Commit())
+	return 1
+}
+
+// Updates the row of the given store and removes all its
+// environment files and labels so they can be inserted again.
+func updateStore(tx *sql.Tx, store types.Store) {
+	_, err := tx.Exec(
+		"UPDATE stores SET path=?, description=?, depth=?, host=? WHERE id=?;",
+		store.Path,
+		store.Description,
+		store.Depth,
+		store.Host,
+		store.Id,
+	)
+	handle(err)
+
+	_, err = tx.Exec("DELETE FROM store_env_files WHERE store_id=?;", store.Id)
+	handle(err)
+
+	_, err = tx.Exec("DELETE FROM store_labels WHERE store_id=?;", store.Id)
+	handle(err)
+}
+
+// Inserts all the environment files and labels of the given
+// store, keeping track of the order of the environment files.
+func insertStoreMetadata(tx *sql.Tx, store types.Store) {
+	for position, env := range store.Envs {
+		_, err := tx.Exec(
+			"INSERT INTO store_env_files(store_id, path, position) VALUES(?,?,?);",
+			store.Id,
+			env,
+			position,
+		)
+		handle(err)
+	}
+
+	for key, value := range store.Labels {
+		_, err := tx.Exec(
+			"INSERT INTO store_labels(store_id, key, value) VALUES(?,?,?);",
+			store.Id,
+			key,
+			value,
+		)
+		handle(err)
+	}
+}
+
+// Updates the status of the container with the given name
+// and returns the number of updated rows.
+//
+// Containers carbon doesn't know about are left alone, which
+// means nothing gets updated.
+func SetContainerStatus(name string, status string) int64 {
+	db, _ := Get()
+
+	res, err := db.Exec("UPDATE containers SET status=? WHERE name=?;", status, name)
+	handle(err)
+
+	affect, err := res.RowsAffected()
+	handle(err)
+
+	return affect
 }
 
 // Deletes a container from the database and returns the
-// deleted ID.
+// number of deleted rows.
+//
+// Only ever deletes the exact row the container was read
+// from, never anything else that happens to look like it.
 func DeleteContainer(container types.Container) int64 {
 	db, _ := Get()
 
-	stmt, err := db.Prepare("DELETE FROM containers WHERE uid=? AND name=?;")
+	stmt, err := db.Prepare("DELETE FROM containers WHERE id=?;")
 	handle(err)
 
-	res, err := stmt.Exec(container.Uid, container.Name)
+	res, err := stmt.Exec(container.Id)
 	handle(err)
 
 	affect, err := res.RowsAffected()
@@ -122,7 +408,7 @@ func DeleteContainer(container types.Container) int64 {
 }
 
 // Deletes a store from the database and returns the
-// deleted ID.
+// number of deleted rows.
 func DeleteStore(store types.Store) int64 {
 	db, _ := Get()
 
@@ -137,3 +423,13 @@ func DeleteStore(store types.Store) int64 {
 
 	return affect
 }
+
+// Turns an empty ID into a NULL so it can be used as
+// a foreign key that doesn't point anywhere.
+func nullable(id int64) interface{} {
+	if id == 0 {
+		return nil
+	}
+
+	return id
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// A unique index that should exist on one of the tables.
+type index struct {
+	Name   string // The name of the index
+	Table  string // The table the index belongs to
+	Column string // The column that should be unique
+	Keep   string // Which of the duplicates to keep when repairing, MIN or MAX id
+
+	// The columns of other tables that point at the rows of this one,
+	// as `table.column`, which follow the duplicate that's kept.
+	References []string
+}
+
+// All the things that should never be duplicated.
+//
+// These live outside of the migrations since they can't be
+// created while there already are duplicates in the tables, which
+// older versions of carbon happily allowed. Instead they get created
+// as soon as nothing is in the way anymore, see Repair().
+var indexes = []index{
+	{Name: "stores_uid", Table: "stores", Column: "uid", Keep: "MIN", References: []string{"containers.store_id"}},
+	{Name: "stores_path", Table: "stores", Column: "path", Keep: "MIN", References: []string{"containers.store_id"}},
+	{Name: "containers_name", Table: "containers", Column: "name", Keep: "MAX"},
+}
+
+// Creates all the unique indexes that don't exist yet.
+//
+// This keeps going even if one of them can't be created so
+// that as many of them as possible are in place. The first
+// error is returned.
+func constrain(db *sql.DB) error {
+	var first error
+
+	for _, idx := range indexes {
+		statement := fmt.Sprintf(
+			"CREATE UNIQUE INDEX IF NOT EXISTS %s ON %s(%s);",
+			idx.Name,
+			idx.Table,
+			idx.Column,
+		)
+
+		_, err := db.Exec(statement)
+		if err != nil && first == nil {
+			first = fmt.Errorf("duplicate %s in %s", idx.Column, idx.Table)
+		}
+	}
+
+	return first
+}
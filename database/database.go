@@ -20,8 +20,14 @@ func Get() (*sql.DB, func() error) {
 		return instance, instance.Close
 	}
 
-	// Try opening the database file
-	db, err := sql.Open("sqlite", helpers.DatabaseFile())
+	// Try opening the database file.
+	//
+	// WAL lets everything that only reads keep reading while
+	// something else is writing, and the busy timeout makes
+	// concurrent writers wait for each other instead of failing.
+	// Foreign keys are off by default in sqlite so they need
+	// to be turned on as well.
+	db, err := sql.Open("sqlite", helpers.DatabaseFile()+pragmas())
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -32,11 +38,25 @@ func Get() (*sql.DB, func() error) {
 		log.Printf("%q: %s\n", err, schema())
 	}
 
+	// Bring older databases up to date
+	migrate(db)
+
+	// Older databases might have duplicates in the way
+	if err := constrain(db); err != nil {
+		log.Printf("%s: run `co2 doctor --db --repair` to fix it\n", err)
+	}
+
 	// Setup
 	instance = db
 	return instance, instance.Close
 }
 
+// Connection parameters that get applied to every new
+// connection to the database.
+func pragmas() string {
+	return "?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)"
+}
+
 // Wrapper for neat handling of any
 // errors that might occur.
 //
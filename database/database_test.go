@@ -33,9 +33,9 @@ func TestContainerInsert(t *testing.T) {
 	defer close()
 
 	// Insert a new container
-	AddContainer(types.Container{})
-	AddContainer(types.Container{})
-	AddContainer(types.Container{})
+	AddContainer(types.Container{Name: "test1"})
+	AddContainer(types.Container{Name: "test2"})
+	AddContainer(types.Container{Name: "test3"})
 
 	// Get all the containers
 	containers := Containers()
@@ -77,3 +77,127 @@ func TestContainerDelete(t *testing.T) {
 		t.Errorf("Expected 2 containers, got %d", len(containers))
 	}
 }
+
+func TestContainerInsertUpdatesContainersWithTheSameName(t *testing.T) {
+	_, close := Get()
+
+	defer cleanup()
+	defer close()
+
+	first := AddContainer(types.Container{Name: "test1", Status: "Created"})
+	second := AddContainer(types.Container{Name: "test1", Status: "Running"})
+
+	containers := Containers()
+
+	if len(containers) != 1 {
+		t.Fatalf("Expected 1 container, got %d", len(containers))
+	}
+
+	if first.Id != second.Id {
+		t.Errorf("Expected the same id, got %d and %d", first.Id, second.Id)
+	}
+
+	if containers[0].Status != "Running" {
+		t.Errorf("Expected the container to be updated, got %s", containers[0].Status)
+	}
+}
+
+func TestSetContainerStatusOnlyUpdatesTheNamedContainer(t *testing.T) {
+	_, close := Get()
+
+	defer cleanup()
+	defer close()
+
+	AddContainer(types.Container{Name: "test1", Status: "Running"})
+	AddContainer(types.Container{Name: "test2", Status: "Running"})
+
+	if updated := SetContainerStatus("test1", "Exited (1)"); updated != 1 {
+		t.Fatalf("Expected 1 updated container, got %d", updated)
+	}
+
+	if updated := SetContainerStatus("unknown", "Exited (1)"); updated != 0 {
+		t.Errorf("Expected unknown containers to be ignored, got %d", updated)
+	}
+
+	for _, container := range Containers() {
+		expected := map[string]string{"test1": "Exited (1)", "test2": "Running"}[container.Name]
+
+		if container.Status != expected {
+			t.Errorf("Expected %s to be %s, got %s", container.Name, expected, container.Status)
+		}
+	}
+}
+
+func TestStoresAndContainersRememberTheirHost(t *testing.T) {
+	_, close := Get()
+
+	defer cleanup()
+	defer close()
+
+	AddStore(types.Store{Uid: "uid1", Path: "path1", Host: "ssh://dev@remote"})
+	AddContainer(types.Container{Name: "test1", Host: "ssh://dev@remote"})
+
+	if host := Stores()[0].Host; host != "ssh://dev@remote" {
+		t.Errorf("Expected the store to remember its host, got %s", host)
+	}
+
+	if host := Containers()[0].Host; host != "ssh://dev@remote" {
+		t.Errorf("Expected the container to remember its host, got %s", host)
+	}
+}
+
+func TestStoreInsertKeepsTheOriginalStoreWithTheSameUid(t *testing.T) {
+	_, close := Get()
+
+	defer cleanup()
+	defer close()
+
+	first := AddStore(types.Store{Uid: "uid1", Path: "path1", Envs: []string{"env1"}})
+	second := AddStore(types.Store{Uid: "uid1", Path: "path2", Envs: []string{"env2", "env3"}})
+
+	stores := Stores()
+
+	if len(stores) != 1 {
+		t.Fatalf("Expected 1 store, got %d", len(stores))
+	}
+
+	if first.Id != second.Id {
+		t.Errorf("Expected the same id, got %d and %d", first.Id, second.Id)
+	}
+
+	if stores[0].Path != "path2" || len(stores[0].Envs) != 2 {
+		t.Errorf("Expected the store to be updated, got %s with %d env files", stores[0].Path, len(stores[0].Envs))
+	}
+}
+
+func TestStorePathsMustBeUnique(t *testing.T) {
+	_, close := Get()
+
+	defer cleanup()
+	defer close()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected adding a store with an existing path to fail")
+		}
+	}()
+
+	AddStore(types.Store{Uid: "uid1", Path: "path1"})
+	AddStore(types.Store{Uid: "uid2", Path: "path1"})
+}
+
+func TestDeletingStoreUnlinksItsContainers(t *testing.T) {
+	_, close := Get()
+
+	defer cleanup()
+	defer close()
+
+	store := AddStore(types.Store{Uid: "uid1", Path: "path1"})
+	AddContainer(types.Container{Name: "test1", StoreId: store.Id})
+
+	DeleteStore(store)
+
+	if Containers()[0].StoreId != 0 {
+		t.Errorf("Expected the container to be unlinked, got store %d", Containers()[0].StoreId)
+	}
+}
@@ -0,0 +1,178 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Looks through the database for anything that shouldn't
+// be there and describes every problem it finds.
+//
+// This covers the integrity of the file itself, rows that point
+// to things that don't exist anymore, duplicates that older versions
+// of carbon allowed, and unique indexes that are missing because of
+// those duplicates.
+func Diagnose() []string {
+	db, _ := Get()
+	problems := []string{}
+
+	// Integrity of the file itself
+	rows, err := db.Query("PRAGMA integrity_check;")
+	handle(err)
+
+	for rows.Next() {
+		var result string
+		handle(rows.Scan(&result))
+
+		if result != "ok" {
+			problems = append(problems, "integrity: "+result)
+		}
+	}
+
+	// Rows pointing to things that don't exist
+	rows, err = db.Query("SELECT \"table\", parent, COUNT(*) FROM pragma_foreign_key_check GROUP BY \"table\", parent;")
+	handle(err)
+
+	for rows.Next() {
+		var table, parent string
+		var count int
+
+		handle(rows.Scan(&table, &parent, &count))
+		problems = append(problems, fmt.Sprintf("%d rows in %s point to missing %s", count, table, parent))
+	}
+
+	// Duplicates and the indexes they're blocking
+	for _, idx := range indexes {
+		query := fmt.Sprintf(
+			"SELECT %s, COUNT(*) FROM %s WHERE %s IS NOT NULL GROUP BY %s HAVING COUNT(*) > 1;",
+			idx.Column,
+			idx.Table,
+			idx.Column,
+			idx.Column,
+		)
+
+		rows, err = db.Query(query)
+		handle(err)
+
+		for rows.Next() {
+			var value sql.NullString
+			var count int
+
+			handle(rows.Scan(&value, &count))
+			problems = append(problems, fmt.Sprintf("%d rows in %s share the %s '%s'", count, idx.Table, idx.Column, value.String))
+		}
+
+		if !indexExists(idx.Name) {
+			problems = append(problems, fmt.Sprintf("%s can be duplicated in %s, the unique index is missing", idx.Column, idx.Table))
+		}
+	}
+
+	return problems
+}
+
+// Fixes everything that Diagnose() can find, apart from
+// the integrity of the file itself, and describes what it did.
+//
+// When there are duplicates, the oldest store is kept so that
+// it keeps its creation date, and the newest container is kept
+// since that's the one that was started last. Anything that pointed
+// at one of the removed duplicates points at the kept one instead.
+//
+// Rows without a value can't be duplicates of each other, the
+// unique indexes don't mind them, so they're always left alone.
+func Repair() []string {
+	db, _ := Get()
+	actions := []string{}
+
+	for _, idx := range indexes {
+		// Which one of its duplicates every row should be
+		kept := fmt.Sprintf(
+			"SELECT %s(id) FROM %s WHERE %s IS NOT NULL GROUP BY %s",
+			idx.Keep,
+			idx.Table,
+			idx.Column,
+			idx.Column,
+		)
+
+		for _, reference := range idx.References {
+			parts := strings.SplitN(reference, ".", 2)
+			table, column := parts[0], parts[1]
+
+			statement := fmt.Sprintf(
+				"UPDATE %s SET %s = (SELECT %s(kept.id) FROM %s kept WHERE kept.%s = (SELECT duplicate.%s FROM %s duplicate WHERE duplicate.id = %s.%s)) "+
+					"WHERE %s IN (SELECT id FROM %s WHERE %s IS NOT NULL AND id NOT IN (%s));",
+				table, column,
+				idx.Keep, idx.Table, idx.Column, idx.Column, idx.Table, table, column,
+				column, idx.Table, idx.Column, kept,
+			)
+
+			res, err := db.Exec(statement)
+			handle(err)
+
+			if affected, _ := res.RowsAffected(); affected > 0 {
+				actions = append(actions, fmt.Sprintf("Moved %d %s over from duplicate %s", affected, table, idx.Table))
+			}
+		}
+
+		statement := fmt.Sprintf(
+			"DELETE FROM %s WHERE %s IS NOT NULL AND id NOT IN (%s);",
+			idx.Table,
+			idx.Column,
+			kept,
+		)
+
+		res, err := db.Exec(statement)
+		handle(err)
+
+		if affected, _ := res.RowsAffected(); affected > 0 {
+			actions = append(actions, fmt.Sprintf("Removed %d duplicate %s from %s", affected, idx.Column, idx.Table))
+		}
+	}
+
+	for _, orphan := range orphans {
+		res, err := db.Exec(orphan.Statement)
+		handle(err)
+
+		if affected, _ := res.RowsAffected(); affected > 0 {
+			actions = append(actions, fmt.Sprintf(orphan.Description, affected))
+		}
+	}
+
+	handle(constrain(db))
+	actions = append(actions, "Made sure all the unique indexes exist")
+
+	return actions
+}
+
+// Statements that clean up rows pointing to stores that
+// don't exist anymore, and what they did.
+var orphans = []struct {
+	Statement   string
+	Description string
+}{
+	{
+		Statement:   "DELETE FROM store_env_files WHERE store_id NOT IN (SELECT id FROM stores);",
+		Description: "Removed %d environment files of missing stores",
+	},
+	{
+		Statement:   "DELETE FROM store_labels WHERE store_id NOT IN (SELECT id FROM stores);",
+		Description: "Removed %d labels of missing stores",
+	},
+	{
+		Statement:   "UPDATE containers SET store_id = NULL WHERE store_id NOT IN (SELECT id FROM stores);",
+		Description: "Unlinked %d containers from missing stores",
+	},
+}
+
+// Checks whether or not the index with the given
+// name exists in the database.
+func indexExists(name string) bool {
+	db, _ := Get()
+
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type='index' AND name=?;", name).Scan(&count)
+	handle(err)
+
+	return count > 0
+}
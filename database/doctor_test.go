@@ -0,0 +1,115 @@
+package database
+
+import (
+	"co2/types"
+	"testing"
+)
+
+func TestDiagnoseFindsNothingInACleanDatabase(t *testing.T) {
+	_, close := Get()
+
+	defer cleanup()
+	defer close()
+
+	AddStore(types.Store{Uid: "uid1", Path: "path1"})
+
+	if problems := Diagnose(); len(problems) != 0 {
+		t.Errorf("Expected no problems, got %v", problems)
+	}
+}
+
+func TestRepairRemovesDuplicatesAndRestoresIndexes(t *testing.T) {
+	db, close := Get()
+
+	defer cleanup()
+	defer close()
+
+	// Pretend to be an old database without the indexes
+	for _, idx := range indexes {
+		_, err := db.Exec("DROP INDEX " + idx.Name + ";")
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err := db.Exec("INSERT INTO stores(uid, path) VALUES('uid1', 'path1');")
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+	}
+
+	if problems := Diagnose(); len(problems) == 0 {
+		t.Error("Expected the duplicates to be found")
+	}
+
+	Repair()
+
+	if problems := Diagnose(); len(problems) != 0 {
+		t.Errorf("Expected no problems after repairing, got %v", problems)
+	}
+
+	if len(Stores()) != 1 {
+		t.Errorf("Expected 1 store after repairing, got %d", len(Stores()))
+	}
+}
+
+func TestRepairMovesContainersOverToTheKeptStore(t *testing.T) {
+	db, close := Get()
+
+	defer cleanup()
+	defer close()
+
+	for _, idx := range indexes {
+		_, err := db.Exec("DROP INDEX " + idx.Name + ";")
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := db.Exec("INSERT INTO stores(uid, path) VALUES('uid1', 'path1');")
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+	}
+
+	var kept, duplicate int64
+	db.QueryRow("SELECT MIN(id), MAX(id) FROM stores;").Scan(&kept, &duplicate)
+
+	AddContainer(types.Container{Name: "container1", Uid: "uid1", StoreId: duplicate})
+
+	Repair()
+
+	containers := Containers()
+	if len(containers) != 1 || containers[0].StoreId != kept {
+		t.Errorf("Expected the container to point at store %d, got %v", kept, containers)
+	}
+}
+
+func TestRepairLeavesRowsWithoutValuesAlone(t *testing.T) {
+	db, close := Get()
+
+	defer cleanup()
+	defer close()
+
+	for _, uid := range []string{"uid1", "uid2"} {
+		_, err := db.Exec("INSERT INTO stores(uid, path) VALUES(?, NULL);", uid)
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+	}
+
+	if problems := Diagnose(); len(problems) != 0 {
+		t.Errorf("Expected empty values not to be duplicates, got %v", problems)
+	}
+
+	Repair()
+
+	var count int
+	db.QueryRow("SELECT COUNT(*) FROM stores;").Scan(&count)
+
+	if count != 2 {
+		t.Errorf("Expected both stores to be kept, got %d", count)
+	}
+}
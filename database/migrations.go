@@ -0,0 +1,102 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// All the changes that have been made to the schema since
+// it was first defined, in the order they were made.
+//
+// The schema() function only ever creates the tables if they
+// don't exist, so anyone who already has a database would never
+// get any of the new columns without these.
+//
+// The index of each migration (+1) is its version, and the latest
+// applied version is kept in the `user_version` pragma of the database
+// itself. Never reorder or remove any of these, only append.
+var migrations = []string{
+	// Stores define how deep we look for carbon.yml files
+	`ALTER TABLE stores ADD COLUMN depth INTEGER NOT NULL DEFAULT 2;`,
+
+	// Stores have any number of environment files, a description and labels
+	`
+	ALTER TABLE stores ADD COLUMN description VARCHAR(256) NOT NULL DEFAULT '';
+
+	CREATE TABLE store_env_files (
+		id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+		store_id INTEGER NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+		path VARCHAR(256) NOT NULL,
+		position INTEGER NOT NULL
+	);
+
+	CREATE TABLE store_labels (
+		id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+		store_id INTEGER NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+		key VARCHAR(64) NOT NULL,
+		value VARCHAR(256) NOT NULL DEFAULT ''
+	);
+
+	INSERT INTO store_env_files(store_id, path, position)
+		SELECT id, env, 0 FROM stores WHERE env IS NOT NULL AND env != '';
+
+	ALTER TABLE stores DROP COLUMN env;
+	`,
+
+	// Containers know which store they were started from
+	`ALTER TABLE containers ADD COLUMN store_id INTEGER REFERENCES stores(id) ON DELETE SET NULL;`,
+
+	// Ports get their own table, and containers know where they came from
+	`
+	CREATE TABLE container_ports (
+		id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+		container_id INTEGER NOT NULL REFERENCES containers(id) ON DELETE CASCADE,
+		host_ip VARCHAR(64) NOT NULL DEFAULT '',
+		host_port INTEGER NOT NULL DEFAULT 0,
+		container_port INTEGER NOT NULL,
+		protocol VARCHAR(8) NOT NULL DEFAULT 'tcp'
+	);
+
+	ALTER TABLE containers ADD COLUMN carbon_file VARCHAR(256) NOT NULL DEFAULT '';
+	ALTER TABLE containers ADD COLUMN start_args VARCHAR(256) NOT NULL DEFAULT '';
+	ALTER TABLE containers DROP COLUMN ports;
+	`,
+
+	// Stores and containers know which docker daemon they belong to
+	`
+	ALTER TABLE stores ADD COLUMN host VARCHAR(256) NOT NULL DEFAULT '';
+	ALTER TABLE containers ADD COLUMN host VARCHAR(256) NOT NULL DEFAULT '';
+	`,
+}
+
+// Runs all the migrations that haven't been applied to the
+// given database yet.
+//
+// Each migration runs within its own transaction, together with
+// the version bump, so a failing migration never leaves the database
+// halfway through.
+func migrate(db *sql.DB) {
+	var version int
+
+	err := db.QueryRow("PRAGMA user_version;").Scan(&version)
+	handle(err)
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := db.Begin()
+		handle(err)
+
+		_, err = tx.Exec(migrations[i])
+		if err != nil {
+			tx.Rollback()
+			panic(err)
+		}
+
+		_, err = tx.Exec(fmt.Sprintf("PRAGMA user_version = %d;", i+1))
+		if err != nil {
+			tx.Rollback()
+			panic(err)
+		}
+
+		handle(tx.Commit())
+	}
+}
@@ -2,8 +2,10 @@ package docker
 
 import (
 	"co2/types"
-	"fmt"
 	"strings"
+
+	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
 )
 
 // Gets all the containers that are currently running on the machine.
@@ -17,17 +19,89 @@ import (
 // but it's also static. Meaning that as long as the container has the same name
 // as its always had and the same image, the resulting unique ID will always
 // be the same.
-func RunningContainers() []types.Container {
-	cli := wrapper().docker
-	containers := cli.RunningContainers()
+//
+// If any labels are given, only the containers that have all of them
+// are returned. Each label is either just a key, which matches any value,
+// or a `key=value` pair which has to match exactly.
+//
+// This talks to the default docker host, and panics if it can't.
+func RunningContainers(labels ...string) []types.Container {
+	containers, err := RunningContainersOn(defaultHost, labels...)
+	if err != nil {
+		panic(err)
+	}
+
+	return containers
+}
+
+// Gets all the containers that are currently running on the given
+// docker host, the same way RunningContainers() does. Every container
+// knows which host it came from.
+//
+// Since other hosts aren't always reachable, this returns the
+// error instead of panicking.
+func RunningContainersOn(host string, labels ...string) ([]types.Container, error) {
+	containers, err := on(host).RunningContainers(labelFilters(labels))
+	if err != nil {
+		return nil, err
+	}
+
+	return parse(host, containers), nil
+}
+
+// Gets all the containers on the given docker host, including the
+// ones that aren't running anymore, the same way RunningContainersOn()
+// does.
+//
+// Docker doesn't say much about why containers stopped when listing
+// them, so every container that isn't running gets inspected as well
+// to find out how it exited.
+func AllContainersOn(host string, labels ...string) ([]types.Container, error) {
+	containers, err := on(host).AllContainers(labelFilters(labels))
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := parse(host, containers)
+
+	for i := range parsed {
+		if parsed[i].State.Running {
+			continue
+		}
+
+		if state, err := inspectOn(host, parsed[i].DockerUid); err == nil {
+			parsed[i].State = state
+		}
+	}
+
+	return parsed, nil
+}
+
+// Turns the given labels into filters for the docker api.
+func labelFilters(labels []string) filters.Args {
+	args := filters.NewArgs()
+	for _, label := range labels {
+		args.Add("label", label)
+	}
+
+	return args
+}
 
+// Converts the containers from the docker api into our own
+// containers, making sure they all know which host they're on.
+func parse(host string, containers []dockerTypes.Container) []types.Container {
 	var parsed = []types.Container{}
 
 	for _, container := range containers {
-		ports := []string{}
+		ports := types.Ports{}
 
 		for _, port := range container.Ports {
-			ports = append(ports, fmt.Sprintf("%d/%s", port.PublicPort, port.Type))
+			ports = append(ports, types.Port{
+				HostIp:        port.IP,
+				HostPort:      int(port.PublicPort),
+				ContainerPort: int(port.PrivatePort),
+				Protocol:      port.Type,
+			})
 		}
 
 		name := strings.TrimPrefix(container.Names[0], "/")
@@ -35,9 +109,15 @@ func RunningContainers() []types.Container {
 		c := types.Container{
 			Name:      name,
 			Image:     container.Image,
-			Ports:     strings.Join(ports, ", "),
+			Ports:     ports,
 			Status:    container.Status,
 			DockerUid: container.ID,
+			Labels:    container.Labels,
+			Host:      host,
+			State: types.ContainerState{
+				Status:  container.State,
+				Running: container.State == "running",
+			},
 		}
 		c.Hash()
 
@@ -0,0 +1,147 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	dockerTypes "github.com/docker/docker/api/types"
+)
+
+// Where the docker cli keeps the credentials of docker hub,
+// which is the only registry it doesn't call by its domain.
+const dockerHubAuth = "https://index.docker.io/v1/"
+
+// The parts of the docker cli configuration that say
+// how to log into the registries.
+type dockerConfig struct {
+	Auths map[string]struct {
+		Auth          string `json:"auth"`
+		IdentityToken string `json:"identitytoken"`
+	} `json:"auths"`
+	CredsStore  string            `json:"credsStore"`
+	CredHelpers map[string]string `json:"credHelpers"`
+}
+
+// Works out the credentials for the registry the given image
+// lives in, the same way the docker cli does after `docker login`,
+// encoded the way the docker api wants them.
+//
+// If there are no credentials for the registry, or they can't be
+// read, nothing is returned and the image is pulled anonymously.
+func registryAuth(image string) string {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return ""
+	}
+
+	registry := reference.Domain(named)
+	if registry == "docker.io" {
+		registry = dockerHubAuth
+	}
+
+	contents, err := os.ReadFile(filepath.Join(configDir(), "config.json"))
+	if err != nil {
+		return ""
+	}
+
+	var config dockerConfig
+	if err := json.Unmarshal(contents, &config); err != nil {
+		return ""
+	}
+
+	auth, ok := credentials(config, registry)
+	if !ok {
+		return ""
+	}
+
+	encoded, err := json.Marshal(auth)
+	if err != nil {
+		return ""
+	}
+
+	return base64.URLEncoding.EncodeToString(encoded)
+}
+
+// Finds the credentials for the given registry within the given
+// configuration, asking the credential helper for them if there
+// is one, or reading them straight from the file if there isn't.
+func credentials(config dockerConfig, registry string) (dockerTypes.AuthConfig, bool) {
+	helper := config.CredsStore
+	if specific, ok := config.CredHelpers[registry]; ok {
+		helper = specific
+	}
+
+	if helper != "" {
+		return fromHelper(helper, registry)
+	}
+
+	for key, entry := range config.Auths {
+		if registryHost(key) != registryHost(registry) {
+			continue
+		}
+
+		auth := dockerTypes.AuthConfig{
+			ServerAddress: registry,
+			IdentityToken: entry.IdentityToken,
+		}
+
+		if decoded, err := base64.StdEncoding.DecodeString(entry.Auth); err == nil {
+			parts := strings.SplitN(string(decoded), ":", 2)
+
+			if len(parts) == 2 {
+				auth.Username, auth.Password = parts[0], parts[1]
+			}
+		}
+
+		return auth, auth.Username != "" || auth.IdentityToken != ""
+	}
+
+	return dockerTypes.AuthConfig{}, false
+}
+
+// Asks the docker credential helper with the given name for
+// the credentials of the given registry.
+func fromHelper(helper, registry string) (dockerTypes.AuthConfig, bool) {
+	command := exec.Command("docker-credential-"+helper, "get")
+	command.Stdin = strings.NewReader(registry)
+
+	output, err := command.Output()
+	if err != nil {
+		return dockerTypes.AuthConfig{}, false
+	}
+
+	var found struct {
+		Username string
+		Secret   string
+	}
+
+	if err := json.NewDecoder(bytes.NewReader(output)).Decode(&found); err != nil {
+		return dockerTypes.AuthConfig{}, false
+	}
+
+	auth := dockerTypes.AuthConfig{ServerAddress: registry}
+
+	// That's how the helpers say they're holding a token
+	if found.Username == "<token>" {
+		auth.IdentityToken = found.Secret
+	} else {
+		auth.Username, auth.Password = found.Username, found.Secret
+	}
+
+	return auth, true
+}
+
+// The registries are written down with or without a scheme and
+// a path, depending on who wrote them, so only the host is compared.
+func registryHost(registry string) string {
+	registry = strings.TrimPrefix(registry, "https://")
+	registry = strings.TrimPrefix(registry, "http://")
+
+	return strings.SplitN(registry, "/", 2)[0]
+}
@@ -0,0 +1,74 @@
+package docker
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dockerTypes "github.com/docker/docker/api/types"
+)
+
+func mockDockerConfig(t *testing.T, contents string) {
+	dir := t.TempDir()
+	os.Setenv("DOCKER_CONFIG", dir)
+	t.Cleanup(func() { os.Unsetenv("DOCKER_CONFIG") })
+
+	os.WriteFile(filepath.Join(dir, "config.json"), []byte(contents), 0644)
+}
+
+func decodeAuth(t *testing.T, encoded string) dockerTypes.AuthConfig {
+	var auth dockerTypes.AuthConfig
+
+	decoded, err := base64.URLEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatal("Expected the auth to be base64, got", encoded)
+	}
+
+	json.Unmarshal(decoded, &auth)
+	return auth
+}
+
+func TestRegistryAuthReadsTheSavedCredentials(t *testing.T) {
+	login := base64.StdEncoding.EncodeToString([]byte("dev:hunter2"))
+	mockDockerConfig(t, `{"auths":{"https://registry.example.com":{"auth":"`+login+`"}}}`)
+
+	auth := decodeAuth(t, registryAuth("registry.example.com/team/api:1.0"))
+
+	if auth.Username != "dev" || auth.Password != "hunter2" || auth.ServerAddress != "registry.example.com" {
+		t.Error("Expected the credentials of the registry, got", auth)
+	}
+}
+
+func TestRegistryAuthUsesTheDockerHubKeyForDockerHubImages(t *testing.T) {
+	login := base64.StdEncoding.EncodeToString([]byte("dev:hunter2"))
+	mockDockerConfig(t, `{"auths":{"https://index.docker.io/v1/":{"auth":"`+login+`"}}}`)
+
+	auth := decodeAuth(t, registryAuth("team/private"))
+
+	if auth.Username != "dev" || auth.ServerAddress != dockerHubAuth {
+		t.Error("Expected the docker hub credentials, got", auth)
+	}
+}
+
+func TestRegistryAuthIsEmptyWithoutCredentials(t *testing.T) {
+	mockDockerConfig(t, `{"auths":{"registry.example.com":{"auth":""}}}`)
+
+	if auth := registryAuth("other.example.com/api"); auth != "" {
+		t.Error("Expected no credentials for another registry, got", auth)
+	}
+
+	if auth := registryAuth("registry.example.com/api"); auth != "" {
+		t.Error("Expected no credentials for an empty login, got", auth)
+	}
+}
+
+func TestRegistryAuthAsksTheCredentialHelper(t *testing.T) {
+	mockDockerConfig(t, `{"credHelpers":{"registry.example.com":"co2-missing-helper"}}`)
+
+	// The helper doesn't exist, so there's nothing to send
+	if auth := registryAuth("registry.example.com/api"); auth != "" {
+		t.Error("Expected no credentials when the helper can't be run, got", auth)
+	}
+}
@@ -0,0 +1,82 @@
+package docker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The parts of a docker context we care about, as the
+// docker cli stores them on disk.
+type dockerContext struct {
+	Endpoints struct {
+		Docker struct {
+			Host          string
+			SkipTLSVerify bool
+		} `json:"docker"`
+	}
+}
+
+// Finds the host of the docker context with the given name,
+// the same one the docker cli would use for `--context`.
+//
+// The default context is whatever the environment says, so
+// that's an empty host.
+//
+// Only the host of a context is ever used, for both the api and
+// the docker cli, so contexts that need anything more than that,
+// like TLS or ssh, are refused instead of half working.
+func ContextHost(name string) (string, error) {
+	if name == "" || name == "default" {
+		return "", nil
+	}
+
+	hash := sha256.Sum256([]byte(name))
+	meta := filepath.Join(configDir(), "contexts", "meta", hex.EncodeToString(hash[:]), "meta.json")
+	tls := filepath.Join(configDir(), "contexts", "tls", hex.EncodeToString(hash[:]))
+
+	contents, err := os.ReadFile(meta)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("docker context '%s' doesn't exist", name)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	var context dockerContext
+	if err := json.Unmarshal(contents, &context); err != nil {
+		return "", err
+	}
+
+	if context.Endpoints.Docker.Host == "" {
+		return "", fmt.Errorf("docker context '%s' has no docker endpoint", name)
+	}
+
+	host := context.Endpoints.Docker.Host
+
+	if strings.HasPrefix(host, "ssh://") {
+		return "", fmt.Errorf("docker context '%s' connects over ssh, which carbon can't do, use `--host` with a tcp or unix socket instead", name)
+	}
+
+	if _, err := os.Stat(tls); err == nil || context.Endpoints.Docker.SkipTLSVerify {
+		return "", fmt.Errorf("docker context '%s' uses TLS, which carbon can't do, use `--host` with the `DOCKER_TLS_VERIFY` and `DOCKER_CERT_PATH` variables instead", name)
+	}
+
+	return host, nil
+}
+
+// Where the docker cli keeps its configuration.
+func configDir() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir
+	}
+
+	home, _ := os.UserHomeDir()
+
+	return filepath.Join(home, ".docker")
+}
@@ -0,0 +1,89 @@
+package docker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Writes a docker context the way the docker cli does, into
+// the given config directory.
+func mockContext(dir, name, endpoint string) {
+	hash := sha256.Sum256([]byte(name))
+	meta := filepath.Join(dir, "contexts", "meta", hex.EncodeToString(hash[:]))
+	os.MkdirAll(meta, 0755)
+	os.WriteFile(
+		filepath.Join(meta, "meta.json"),
+		[]byte(`{"Name":"`+name+`","Endpoints":{"docker":`+endpoint+`}}`),
+		0644,
+	)
+}
+
+func TestContextHostReadsTheContextFromTheDockerConfig(t *testing.T) {
+	dir := t.TempDir()
+	os.Setenv("DOCKER_CONFIG", dir)
+	defer os.Unsetenv("DOCKER_CONFIG")
+
+	mockContext(dir, "remote", `{"Host":"tcp://10.0.0.2:2375"}`)
+
+	host, err := ContextHost("remote")
+	if err != nil {
+		t.Fatal("Expected no error, got", err)
+	}
+
+	if host != "tcp://10.0.0.2:2375" {
+		t.Error("Expected the host of the context, got", host)
+	}
+}
+
+func TestContextHostFailsForUnknownContexts(t *testing.T) {
+	os.Setenv("DOCKER_CONFIG", t.TempDir())
+	defer os.Unsetenv("DOCKER_CONFIG")
+
+	if _, err := ContextHost("missing"); err == nil {
+		t.Error("Expected an error for a context that doesn't exist")
+	}
+}
+
+func TestContextHostOfTheDefaultContextIsEmpty(t *testing.T) {
+	host, err := ContextHost("default")
+
+	if host != "" || err != nil {
+		t.Error("Expected the default context to use the environment, got", host, err)
+	}
+}
+
+func TestContextHostRefusesSshContexts(t *testing.T) {
+	dir := t.TempDir()
+	os.Setenv("DOCKER_CONFIG", dir)
+	defer os.Unsetenv("DOCKER_CONFIG")
+
+	mockContext(dir, "remote", `{"Host":"ssh://dev@remote"}`)
+
+	if _, err := ContextHost("remote"); err == nil {
+		t.Error("Expected an error for a context that connects over ssh")
+	}
+}
+
+func TestContextHostRefusesTlsContexts(t *testing.T) {
+	dir := t.TempDir()
+	os.Setenv("DOCKER_CONFIG", dir)
+	defer os.Unsetenv("DOCKER_CONFIG")
+
+	mockContext(dir, "skipping", `{"Host":"tcp://10.0.0.2:2376","SkipTLSVerify":true}`)
+
+	if _, err := ContextHost("skipping"); err == nil {
+		t.Error("Expected an error for a context that skips TLS verification")
+	}
+
+	mockContext(dir, "secure", `{"Host":"tcp://10.0.0.2:2376"}`)
+
+	hash := sha256.Sum256([]byte("secure"))
+	os.MkdirAll(filepath.Join(dir, "contexts", "tls", hex.EncodeToString(hash[:]), "docker"), 0755)
+
+	if _, err := ContextHost("secure"); err == nil {
+		t.Error("Expected an error for a context that has TLS material")
+	}
+}
@@ -0,0 +1,244 @@
+package docker
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/client"
+)
+
+// Copies the given path out of the container on the given
+// docker host, empty for the default one, into the given local path.
+//
+// This works the same way `docker cp` does. If the destination is a
+// directory that already exists, the copy ends up inside of it,
+// otherwise it's created with the name of the destination.
+func CopyFrom(host, container, src, dst string) error {
+	stream, stat, err := on(host).CopyFrom(container, src)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	target := dst
+	if info, err := os.Stat(dst); err == nil && info.IsDir() {
+		target = filepath.Join(dst, stat.Name)
+	}
+
+	return untar(stream, stat.Name, target)
+}
+
+// Copies the given local path into the container on the given
+// docker host, empty for the default one.
+//
+// This works the same way `docker cp` does. If the destination is a
+// directory that already exists in the container, the copy ends up
+// inside of it, otherwise it's created with the name of the destination.
+func CopyTo(host, container, src, dst string) error {
+	if _, err := os.Stat(src); err != nil {
+		return err
+	}
+
+	dir, name := dst, filepath.Base(src)
+
+	// Only a destination that doesn't exist yet gets created,
+	// anything else going wrong means nothing can be copied.
+	stat, err := on(host).StatPath(container, dst)
+	if err != nil && !client.IsErrNotFound(err) {
+		return err
+	}
+
+	if err != nil || !stat.Mode.IsDir() {
+		dir, name = path.Dir(dst), path.Base(dst)
+	}
+
+	reader, writer := io.Pipe()
+
+	go func() {
+		writer.CloseWithError(archive(writer, src, name))
+	}()
+
+	err = on(host).CopyTo(container, dir, reader)
+	reader.Close()
+
+	return err
+}
+
+// Writes the given local path, and everything within it if it's
+// a directory, into a tar archive where it's called by the given name.
+func archive(w io.Writer, src, name string) error {
+	archived := tar.NewWriter(w)
+
+	err := filepath.Walk(src, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relative, err := filepath.Rel(src, file)
+		if err != nil {
+			return err
+		}
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+
+		header.Name = path.Join(name, filepath.ToSlash(relative))
+		if info.IsDir() {
+			header.Name += "/"
+		}
+
+		if err := archived.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		opened, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer opened.Close()
+
+		_, err = io.Copy(archived, opened)
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return archived.Close()
+}
+
+// Extracts the given tar archive, where everything lives under
+// the given root, into the given local path instead.
+//
+// Nothing is ever written outside of the given path, no matter
+// what the archive says. Symlinks that point outside of it are
+// refused, and nothing is ever written through an existing one.
+func untar(r io.Reader, root, target string) error {
+	archived := tar.NewReader(r)
+
+	for {
+		header, err := archived.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		name := strings.TrimSuffix(header.Name, "/")
+		if name != root && !strings.HasPrefix(name, root+"/") {
+			continue
+		}
+
+		file := filepath.Join(target, filepath.FromSlash(strings.TrimPrefix(name, root)))
+		// The parents of the destination itself are up to the user
+		if !within(target, file) || (file != target && !resolvesWithin(target, filepath.Dir(file))) {
+			return fmt.Errorf("refusing to write outside of %s: %s", target, header.Name)
+		}
+
+		mode := os.FileMode(header.Mode).Perm()
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if info, err := os.Lstat(file); err == nil && !info.IsDir() {
+				os.Remove(file)
+			}
+
+			if err := os.MkdirAll(file, mode|0700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+				return err
+			}
+
+			// Whatever's already there gets replaced, never written through
+			if info, err := os.Lstat(file); err == nil && !info.IsDir() {
+				os.Remove(file)
+			}
+
+			created, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_EXCL, mode)
+			if err != nil {
+				return err
+			}
+
+			_, err = io.Copy(created, archived)
+			created.Close()
+
+			if err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			link := header.Linkname
+			if !filepath.IsAbs(link) {
+				link = filepath.Join(filepath.Dir(file), link)
+			}
+
+			if !within(target, link) {
+				return fmt.Errorf("refusing to link outside of %s: %s -> %s", target, header.Name, header.Linkname)
+			}
+
+			if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+				return err
+			}
+
+			os.Remove(file)
+
+			if err := os.Symlink(header.Linkname, file); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Checks whether the given path is the given directory, or
+// somewhere within it, going only by what the paths say.
+func within(dir, file string) bool {
+	relative, err := filepath.Rel(dir, file)
+
+	return err == nil && relative != ".." && !strings.HasPrefix(relative, ".."+string(filepath.Separator))
+}
+
+// Checks whether the given path is still within the given directory
+// once all the symlinks that already exist along the way are followed.
+func resolvesWithin(dir, file string) bool {
+	return within(resolve(dir), resolve(file))
+}
+
+// Follows all the symlinks of the part of the given path that
+// already exists, and puts the rest of the path back on top.
+func resolve(file string) string {
+	missing := ""
+
+	for current := file; ; current = filepath.Dir(current) {
+		if resolved, err := filepath.EvalSymlinks(current); err == nil {
+			return filepath.Join(resolved, missing)
+		}
+
+		if filepath.Dir(current) == current {
+			return file
+		}
+
+		missing = filepath.Join(filepath.Base(current), missing)
+	}
+}
@@ -0,0 +1,233 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/4khara/replica"
+	dockerTypes "github.com/docker/docker/api/types"
+)
+
+func mockArchive(files map[string]string) io.ReadCloser {
+	var buffer bytes.Buffer
+	archived := tar.NewWriter(&buffer)
+
+	for name, contents := range files {
+		archived.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents)), Typeflag: tar.TypeReg})
+		archived.Write([]byte(contents))
+	}
+
+	archived.Close()
+	return io.NopCloser(&buffer)
+}
+
+func readArchive(t *testing.T, archive []byte) map[string]string {
+	files := map[string]string{}
+	archived := tar.NewReader(bytes.NewReader(archive))
+
+	for {
+		header, err := archived.Next()
+		if err == io.EOF {
+			return files
+		}
+
+		if err != nil {
+			t.Fatal("Expected a valid archive, got", err)
+		}
+
+		contents, _ := io.ReadAll(archived)
+		files[header.Name] = string(contents)
+	}
+}
+
+func TestCopyFromRenamesTheFileToTheDestination(t *testing.T) {
+	before()
+
+	dir := t.TempDir()
+	replica.Mocks.SetReturnValues("CopyFrom", mockArchive(map[string]string{"app.log": "hello"}), dockerTypes.ContainerPathStat{Name: "app.log"}, nil)
+
+	if err := CopyFrom("", "container1", "/var/log/app.log", filepath.Join(dir, "copy.log")); err != nil {
+		t.Fatal("Expected no error, got", err)
+	}
+
+	contents, _ := os.ReadFile(filepath.Join(dir, "copy.log"))
+	if string(contents) != "hello" {
+		t.Error("CopyFrom should write the file to the destination, got", string(contents))
+	}
+}
+
+func TestCopyFromCopiesIntoExistingDirectories(t *testing.T) {
+	before()
+
+	dir := t.TempDir()
+	replica.Mocks.SetReturnValues("CopyFrom", mockArchive(map[string]string{
+		"config/app.yml":   "a",
+		"config/db/db.yml": "b",
+	}), dockerTypes.ContainerPathStat{Name: "config", Mode: os.ModeDir}, nil)
+
+	if err := CopyFrom("", "container1", "/etc/config", dir); err != nil {
+		t.Fatal("Expected no error, got", err)
+	}
+
+	for file, expected := range map[string]string{"config/app.yml": "a", "config/db/db.yml": "b"} {
+		contents, _ := os.ReadFile(filepath.Join(dir, file))
+		if string(contents) != expected {
+			t.Errorf("Expected %s to contain %q, got %q", file, expected, contents)
+		}
+	}
+}
+
+func TestCopyFromRefusesToWriteOutsideOfTheDestination(t *testing.T) {
+	before()
+
+	dir := t.TempDir()
+	replica.Mocks.SetReturnValues("CopyFrom", mockArchive(map[string]string{"config/../../evil": "a"}), dockerTypes.ContainerPathStat{Name: "config"}, nil)
+
+	if err := CopyFrom("", "container1", "/etc/config", filepath.Join(dir, "config")); err == nil {
+		t.Error("CopyFrom should refuse archives that escape the destination")
+	}
+}
+
+func TestCopyToCopiesIntoExistingDirectories(t *testing.T) {
+	before()
+
+	src := filepath.Join(t.TempDir(), "fixture.json")
+	os.WriteFile(src, []byte("{}"), 0644)
+
+	if err := CopyTo("", "container1", src, "/app"); err != nil {
+		t.Fatal("Expected no error, got", err)
+	}
+
+	params := replica.Mocks.GetCallParams("CopyTo")[0]
+	files := readArchive(t, params[2].([]byte))
+
+	if params[1] != "/app" || files["fixture.json"] != "{}" {
+		t.Error("CopyTo should copy the file into the directory, got", params[1], files)
+	}
+}
+
+func TestCopyToRenamesToTheDestination(t *testing.T) {
+	before()
+
+	src := t.TempDir()
+	os.Mkdir(filepath.Join(src, "nested"), 0755)
+	os.WriteFile(filepath.Join(src, "nested", "seed.sql"), []byte("select 1;"), 0644)
+
+	replica.Mocks.SetReturnValues("StatPath", dockerTypes.ContainerPathStat{}, notFoundError{})
+
+	if err := CopyTo("", "container1", src, "/docker-entrypoint-initdb.d"); err != nil {
+		t.Fatal("Expected no error, got", err)
+	}
+
+	params := replica.Mocks.GetCallParams("CopyTo")[0]
+	files := readArchive(t, params[2].([]byte))
+
+	if params[1] != "/" || files["docker-entrypoint-initdb.d/nested/seed.sql"] != "select 1;" {
+		t.Error("CopyTo should name the copy after the destination, got", params[1], files)
+	}
+}
+
+func linkArchive(link string, files ...string) *bytes.Buffer {
+	var buffer bytes.Buffer
+	archived := tar.NewWriter(&buffer)
+
+	archived.WriteHeader(&tar.Header{Name: "config/x", Linkname: link, Typeflag: tar.TypeSymlink})
+
+	for _, name := range files {
+		archived.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: 4, Typeflag: tar.TypeReg})
+		archived.Write([]byte("evil"))
+	}
+
+	archived.Close()
+	return &buffer
+}
+
+func TestUntarRefusesSymlinksThatPointOutside(t *testing.T) {
+	dir := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "bashrc")
+
+	if err := untar(linkArchive(outside), "config", filepath.Join(dir, "config")); err == nil {
+		t.Error("untar should refuse symlinks that point outside of the destination")
+	}
+
+	if err := untar(linkArchive("../../bashrc"), "config", filepath.Join(dir, "config")); err == nil {
+		t.Error("untar should refuse relative symlinks that point outside of the destination")
+	}
+}
+
+func TestUntarNeverWritesThroughExistingSymlinks(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "config")
+	outside := t.TempDir()
+	victim := filepath.Join(outside, "bashrc")
+
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(victim, []byte("safe"), 0644)
+	os.Symlink(victim, filepath.Join(dir, "x"))
+	os.Symlink(outside, filepath.Join(dir, "nested"))
+
+	var buffer bytes.Buffer
+	archived := tar.NewWriter(&buffer)
+	archived.WriteHeader(&tar.Header{Name: "config/x", Mode: 0644, Size: 4, Typeflag: tar.TypeReg})
+	archived.Write([]byte("evil"))
+	archived.Close()
+
+	if err := untar(&buffer, "config", dir); err != nil {
+		t.Fatal("untar should replace the symlink, got", err)
+	}
+
+	if contents, _ := os.ReadFile(victim); string(contents) != "safe" {
+		t.Error("untar should never write through an existing symlink")
+	}
+
+	if contents, _ := os.ReadFile(filepath.Join(dir, "x")); string(contents) != "evil" {
+		t.Error("untar should write the file in place of the symlink, got", string(contents))
+	}
+
+	buffer.Reset()
+	archived = tar.NewWriter(&buffer)
+	archived.WriteHeader(&tar.Header{Name: "config/nested/bashrc", Mode: 0644, Size: 4, Typeflag: tar.TypeReg})
+	archived.Write([]byte("evil"))
+	archived.Close()
+
+	if err := untar(&buffer, "config", dir); err == nil {
+		t.Error("untar should refuse to write through symlinked directories")
+	}
+
+	if contents, _ := os.ReadFile(victim); string(contents) != "safe" {
+		t.Error("untar should never write outside of the destination")
+	}
+}
+
+func TestUntarKeepsSymlinksWithinTheDestination(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "config")
+
+	if err := untar(linkArchive("real"), "config", dir); err != nil {
+		t.Fatal("untar should keep symlinks that stay within the destination, got", err)
+	}
+
+	if link, _ := os.Readlink(filepath.Join(dir, "x")); link != "real" {
+		t.Error("untar should create the symlink as it was, got", link)
+	}
+}
+
+func TestCopyToFailsWhenTheDestinationCantBeChecked(t *testing.T) {
+	before()
+
+	src := filepath.Join(t.TempDir(), "fixture.json")
+	os.WriteFile(src, []byte("{}"), 0644)
+
+	replica.Mocks.SetReturnValues("StatPath", dockerTypes.ContainerPathStat{}, errors.New("permission denied"))
+
+	if err := CopyTo("", "container1", src, "/app"); err == nil {
+		t.Error("CopyTo should fail when the destination can't be checked")
+	}
+
+	if replica.Mocks.GetCallCount("CopyTo") != 0 {
+		t.Error("CopyTo shouldn't copy anything when the destination can't be checked")
+	}
+}
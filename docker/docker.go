@@ -7,6 +7,14 @@ import (
 var once sync.Once
 var instance *impl
 
+// The docker host that's used whenever no specific
+// one is requested, empty for whatever the environment says.
+var defaultHost string
+
+// The network every carbon service joins, empty for
+// whatever the environment says.
+var sharedNetwork string
+
 // Simple implementation to make the wrapper
 // accessible without generating an abundance of instances.
 type impl struct {
@@ -37,3 +45,34 @@ func CustomWrapper(docker DockerWrapper) *impl {
 
 	return instance
 }
+
+// Makes every call to the docker api that doesn't ask for
+// a specific host go to the given one instead of the one
+// from the environment.
+func UseHost(host string) {
+	defaultHost = host
+}
+
+// Makes every carbon service join the given network instead
+// of the one from the environment, if it isn't empty.
+func UseNetwork(name string) {
+	sharedNetwork = name
+}
+
+// The docker host that's currently used by default, empty
+// if it's the one from the environment.
+func DefaultHost() string {
+	return defaultHost
+}
+
+// Returns a docker wrapper that talks to the given host.
+//
+// Custom wrappers get every call no matter the host since
+// they're only ever used in tests, where there's no real daemon.
+func on(host string) DockerWrapper {
+	if _, ok := wrapper().docker.(*Wrapper); ok {
+		return &Wrapper{Host: host}
+	}
+
+	return wrapper().docker
+}
@@ -2,26 +2,38 @@ package docker
 
 import (
 	"co2/helpers"
+	"errors"
+	"fmt"
+	"io"
+	"os"
 	"strings"
 	"testing"
 
 	"github.com/4khara/replica"
 	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
 )
 
 type MockWrapper struct{}
 
-func (w *MockWrapper) RunningContainers() []dockerTypes.Container {
-	_, rv := replica.MockFn()
+func (w *MockWrapper) RunningContainers(filters filters.Args) ([]dockerTypes.Container, error) {
+	_, rv := replica.MockFn(filters)
 
 	if rv != nil {
 		var containers []dockerTypes.Container
+		var err error
 
 		if rv[0] != nil {
 			containers = rv[0].([]dockerTypes.Container)
 		}
 
-		return containers
+		if len(rv) > 1 {
+			err = mockErr(rv[1])
+		}
+
+		return containers, err
 	}
 
 	return []dockerTypes.Container{
@@ -35,7 +47,244 @@ func (w *MockWrapper) RunningContainers() []dockerTypes.Container {
 			Image: "image2",
 			Names: []string{"/container2"},
 		},
+	}, nil
+}
+
+func (w *MockWrapper) AllContainers(filters filters.Args) ([]dockerTypes.Container, error) {
+	_, rv := replica.MockFn(filters)
+
+	if rv != nil {
+		var containers []dockerTypes.Container
+
+		if rv[0] != nil {
+			containers = rv[0].([]dockerTypes.Container)
+		}
+
+		return containers, nil
+	}
+
+	return w.RunningContainers(filters)
+}
+
+func (w *MockWrapper) Inspect(id string) (dockerTypes.ContainerJSON, error) {
+	_, rv := replica.MockFn(id)
+
+	if rv != nil {
+		return rv[0].(dockerTypes.ContainerJSON), mockErr(rv[1])
+	}
+
+	return dockerTypes.ContainerJSON{
+		ContainerJSONBase: &dockerTypes.ContainerJSONBase{
+			ID:    id,
+			State: &dockerTypes.ContainerState{Status: "running", Running: true},
+		},
+	}, nil
+}
+
+func (w *MockWrapper) Remove(id string, options dockerTypes.ContainerRemoveOptions) error {
+	_, rv := replica.MockFn(id, options)
+
+	if rv != nil {
+		return mockErr(rv[0])
+	}
+
+	return nil
+}
+
+func (w *MockWrapper) Logs(id string, options dockerTypes.ContainerLogsOptions) (io.ReadCloser, error) {
+	_, rv := replica.MockFn(id, options)
+
+	if rv != nil {
+		var stream io.ReadCloser
+
+		if rv[0] != nil {
+			stream = rv[0].(io.ReadCloser)
+		}
+
+		return stream, mockErr(rv[1])
 	}
+
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
+func (w *MockWrapper) Events(filters filters.Args) (<-chan events.Message, <-chan error) {
+	_, rv := replica.MockFn(filters)
+
+	messages := make(chan events.Message)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(messages)
+
+		if rv != nil {
+			for _, message := range rv[0].([]events.Message) {
+				messages <- message
+			}
+		}
+	}()
+
+	return messages, errs
+}
+
+func (w *MockWrapper) Stats(id string) (dockerTypes.StatsJSON, error) {
+	_, rv := replica.MockFn(id)
+
+	if rv != nil {
+		return rv[0].(dockerTypes.StatsJSON), mockErr(rv[1])
+	}
+
+	return dockerTypes.StatsJSON{}, nil
+}
+
+func (w *MockWrapper) Pull(image string) (io.ReadCloser, error) {
+	_, rv := replica.MockFn(image)
+
+	if rv != nil {
+		var stream io.ReadCloser
+
+		if rv[0] != nil {
+			stream = rv[0].(io.ReadCloser)
+		}
+
+		return stream, mockErr(rv[1])
+	}
+
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
+func (w *MockWrapper) Images(filters filters.Args) ([]dockerTypes.ImageSummary, error) {
+	_, rv := replica.MockFn(filters)
+
+	if rv != nil {
+		var images []dockerTypes.ImageSummary
+
+		if rv[0] != nil {
+			images = rv[0].([]dockerTypes.ImageSummary)
+		}
+
+		return images, mockErr(rv[1])
+	}
+
+	return []dockerTypes.ImageSummary{}, nil
+}
+
+func (w *MockWrapper) StatPath(id string, path string) (dockerTypes.ContainerPathStat, error) {
+	_, rv := replica.MockFn(id, path)
+
+	if rv != nil {
+		return rv[0].(dockerTypes.ContainerPathStat), mockErr(rv[1])
+	}
+
+	return dockerTypes.ContainerPathStat{Name: path, Mode: os.ModeDir}, nil
+}
+
+func (w *MockWrapper) CopyFrom(id string, path string) (io.ReadCloser, dockerTypes.ContainerPathStat, error) {
+	_, rv := replica.MockFn(id, path)
+
+	if rv != nil {
+		var stream io.ReadCloser
+
+		if rv[0] != nil {
+			stream = rv[0].(io.ReadCloser)
+		}
+
+		return stream, rv[1].(dockerTypes.ContainerPathStat), mockErr(rv[2])
+	}
+
+	return io.NopCloser(strings.NewReader("")), dockerTypes.ContainerPathStat{}, nil
+}
+
+// The archive is read right away so that whatever is writing
+// it can finish, and so that tests can look at what was sent.
+func (w *MockWrapper) CopyTo(id string, path string, content io.Reader) error {
+	archive, _ := io.ReadAll(content)
+	_, rv := replica.MockFn(id, path, archive)
+
+	if rv != nil {
+		return mockErr(rv[0])
+	}
+
+	return nil
+}
+
+func (w *MockWrapper) NetworkInspect(name string) (dockerTypes.NetworkResource, error) {
+	_, rv := replica.MockFn(name)
+
+	if rv != nil {
+		return rv[0].(dockerTypes.NetworkResource), mockErr(rv[1])
+	}
+
+	return dockerTypes.NetworkResource{Name: name, Driver: "bridge"}, nil
+}
+
+func (w *MockWrapper) NetworkCreate(name string, options dockerTypes.NetworkCreate) error {
+	_, rv := replica.MockFn(name, options)
+
+	if rv != nil {
+		return mockErr(rv[0])
+	}
+
+	return nil
+}
+
+func (w *MockWrapper) DiskUsage() (dockerTypes.DiskUsage, error) {
+	_, rv := replica.MockFn()
+
+	if rv != nil {
+		return rv[0].(dockerTypes.DiskUsage), mockErr(rv[1])
+	}
+
+	return dockerTypes.DiskUsage{}, nil
+}
+
+func (w *MockWrapper) VolumeRemove(name string, force bool) error {
+	_, rv := replica.MockFn(name, force)
+
+	if rv != nil {
+		return mockErr(rv[0])
+	}
+
+	return nil
+}
+
+func (w *MockWrapper) Create(config *container.Config, host *container.HostConfig) (string, error) {
+	count, rv := replica.MockFn(config, host)
+
+	if rv != nil {
+		return rv[0].(string), mockErr(rv[1])
+	}
+
+	return fmt.Sprintf("created-%d", count), nil
+}
+
+func (w *MockWrapper) Start(id string) error {
+	_, rv := replica.MockFn(id)
+
+	if rv != nil {
+		return mockErr(rv[0])
+	}
+
+	return nil
+}
+
+func (w *MockWrapper) Wait(id string) (int64, error) {
+	_, rv := replica.MockFn(id)
+
+	if rv != nil {
+		return rv[0].(int64), mockErr(rv[1])
+	}
+
+	return 0, nil
+}
+
+// Converts a mocked return value into an error,
+// since nil can't be asserted into one.
+func mockErr(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	return value.(error)
 }
 
 func before() {
@@ -107,3 +356,95 @@ func TestContainerKeys(t *testing.T) {
 		t.Error("Expected", expected[1], "got", containers[1].Uid)
 	}
 }
+
+func TestRunningContainersFiltersOnLabels(t *testing.T) {
+	before()
+
+	RunningContainers("co2.service", "co2.store=abcd")
+
+	args := replica.Mocks.GetCallParams("RunningContainers")[0][0].(filters.Args)
+	labels := args.Get("label")
+
+	if len(labels) != 2 {
+		t.Fatal("Expected 2 label filters, got", labels)
+	}
+
+	if !args.ExactMatch("label", "co2.service") || !args.ExactMatch("label", "co2.store=abcd") {
+		t.Error("Expected the given labels to be used as filters, got", labels)
+	}
+}
+
+func TestRunningContainersKeepsLabels(t *testing.T) {
+	before()
+
+	replica.Mocks.SetReturnValues("RunningContainers", []dockerTypes.Container{
+		{
+			ID:     "1",
+			Image:  "image1",
+			Names:  []string{"/container1"},
+			Labels: map[string]string{"co2.service": "api"},
+		},
+	})
+
+	containers := RunningContainers()
+
+	if containers[0].Labels["co2.service"] != "api" {
+		t.Error("Expected the labels to be kept, got", containers[0].Labels)
+	}
+}
+
+func TestRunningContainersOnRemembersTheHost(t *testing.T) {
+	before()
+
+	containers, err := RunningContainersOn("tcp://10.0.0.2:2375")
+	if err != nil {
+		t.Fatal("Expected no error, got", err)
+	}
+
+	for _, container := range containers {
+		if container.Host != "tcp://10.0.0.2:2375" {
+			t.Error("Expected every container to know its host, got", container.Host)
+		}
+	}
+}
+
+func TestRunningContainersOnReturnsErrors(t *testing.T) {
+	before()
+
+	replica.Mocks.SetReturnValues("RunningContainers", nil, errors.New("connection refused"))
+
+	if _, err := RunningContainersOn("tcp://10.0.0.2:2375"); err == nil {
+		t.Error("Expected the connection error to be returned")
+	}
+}
+
+func TestAllContainersOnInspectsStoppedContainers(t *testing.T) {
+	before()
+
+	replica.Mocks.SetReturnValues("AllContainers", []dockerTypes.Container{
+		{ID: "1", Image: "image1", Names: []string{"/running"}, State: "running"},
+		{ID: "2", Image: "image2", Names: []string{"/crashed"}, State: "exited"},
+	})
+	replica.Mocks.SetReturnValues("Inspect", dockerTypes.ContainerJSON{
+		ContainerJSONBase: &dockerTypes.ContainerJSONBase{
+			State: &dockerTypes.ContainerState{Status: "exited", ExitCode: 137, OOMKilled: true},
+		},
+	}, nil)
+
+	containers, err := AllContainersOn("")
+	if err != nil {
+		t.Fatal("Expected no error, got", err)
+	}
+
+	if !containers[0].State.Running {
+		t.Error("Expected the running container to be running")
+	}
+
+	if containers[1].State.ExitCode != 137 || !containers[1].State.OOMKilled {
+		t.Errorf("Expected the crashed container to be inspected, got %+v", containers[1].State)
+	}
+
+	if replica.Mocks.GetCallCount("Inspect") != 1 {
+		t.Error("Expected only the stopped container to be inspected, got", replica.Mocks.GetCallCount("Inspect"))
+	}
+}
@@ -0,0 +1,73 @@
+package docker
+
+import (
+	"co2/types"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// Subscribes to everything that happens to containers that have
+// all of the given labels, and only to the given actions.
+//
+// Labels work the same way as in RunningContainers(). The returned
+// channels stay open for as long as the connection to the daemon does,
+// the error channel only ever gets a value when it breaks.
+func Events(actions []string, labels ...string) (<-chan types.ContainerEvent, <-chan error) {
+	args := filters.NewArgs(filters.Arg("type", events.ContainerEventType))
+
+	for _, action := range actions {
+		args.Add("event", action)
+	}
+
+	for _, label := range labels {
+		args.Add("label", label)
+	}
+
+	messages, errs := wrapper().docker.Events(args)
+	parsed := make(chan types.ContainerEvent)
+
+	go func() {
+		defer close(parsed)
+
+		for message := range messages {
+			parsed <- event(message)
+		}
+	}()
+
+	return parsed, errs
+}
+
+// Converts a raw docker event into our own event.
+//
+// Docker puts the labels of the container into the same attributes
+// as the name and the exit code, so those are taken out. Health checks
+// come in as `health_status: healthy`, so the status is split from it.
+func event(message events.Message) types.ContainerEvent {
+	labels := map[string]string{}
+	for key, value := range message.Actor.Attributes {
+		labels[key] = value
+	}
+
+	parsed := types.ContainerEvent{
+		Action:    message.Action,
+		DockerUid: message.Actor.ID,
+		Name:      labels["name"],
+		ExitCode:  labels["exitCode"],
+		Time:      time.Unix(0, message.TimeNano),
+	}
+
+	delete(labels, "name")
+	delete(labels, "image")
+	delete(labels, "exitCode")
+	parsed.Labels = labels
+
+	if parts := strings.SplitN(message.Action, ":", 2); len(parts) == 2 {
+		parsed.Action = parts[0]
+		parsed.Health = strings.TrimSpace(parts[1])
+	}
+
+	return parsed
+}
@@ -0,0 +1,71 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/4khara/replica"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+)
+
+func TestEventsFiltersOnActionsAndLabels(t *testing.T) {
+	before()
+
+	parsed, _ := Events([]string{"start", "die"}, "co2.service")
+	for range parsed {
+	}
+
+	args := replica.Mocks.GetCallParams("Events")[0][0].(filters.Args)
+
+	if !args.ExactMatch("type", "container") {
+		t.Error("Expected only container events, got", args.Get("type"))
+	}
+
+	if !args.ExactMatch("event", "start") || !args.ExactMatch("event", "die") {
+		t.Error("Expected the given actions to be filtered on, got", args.Get("event"))
+	}
+
+	if !args.ExactMatch("label", "co2.service") {
+		t.Error("Expected the given labels to be filtered on, got", args.Get("label"))
+	}
+}
+
+func TestEventsParsesTheMessages(t *testing.T) {
+	before()
+
+	replica.Mocks.SetReturnValues("Events", []events.Message{
+		{
+			Action: "die",
+			Actor: events.Actor{
+				ID: "abcd",
+				Attributes: map[string]string{
+					"name":        "api-abcdefghij",
+					"image":       "nginx",
+					"exitCode":    "137",
+					"co2.service": "api",
+				},
+			},
+			TimeNano: 1000,
+		},
+		{
+			Action: "health_status: unhealthy",
+			Actor:  events.Actor{ID: "abcd"},
+		},
+	})
+
+	parsed, _ := Events(nil)
+
+	died := <-parsed
+	if died.Action != "die" || died.ExitCode != "137" || died.Name != "api-abcdefghij" {
+		t.Errorf("Expected a parsed die event, got %+v", died)
+	}
+
+	if len(died.Labels) != 1 || died.Labels["co2.service"] != "api" {
+		t.Error("Expected only the container labels to be kept, got", died.Labels)
+	}
+
+	health := <-parsed
+	if health.Action != "health_status" || health.Health != "unhealthy" {
+		t.Errorf("Expected the health status to be split from the action, got %+v", health)
+	}
+}
@@ -0,0 +1,85 @@
+package docker
+
+import (
+	"co2/types"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+// Pulls the given image, handing every step of the way to
+// the given function as a single line of progress.
+//
+// Docker sends an update for every few bytes of every layer, so
+// only the ones where a layer moves onto something new are passed
+// along, otherwise there would be a flood of identical lines.
+func Pull(image string, progress func(line string)) error {
+	stream, err := wrapper().docker.Pull(image)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	decoder := json.NewDecoder(stream)
+	layers := map[string]string{}
+
+	for {
+		var message jsonmessage.JSONMessage
+
+		if err := decoder.Decode(&message); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+
+			return err
+		}
+
+		if message.Error != nil {
+			return errors.New(message.Error.Message)
+		}
+
+		if message.ID == "" {
+			progress(message.Status)
+			continue
+		}
+
+		if layers[message.ID] == message.Status {
+			continue
+		}
+
+		layers[message.ID] = message.Status
+		progress(message.ID + ": " + message.Status)
+	}
+}
+
+// Returns all the images that are present locally.
+func Images() ([]types.Image, error) {
+	summaries, err := wrapper().docker.Images(filters.NewArgs())
+	if err != nil {
+		return nil, err
+	}
+
+	return parseImages(summaries), nil
+}
+
+// Converts the images docker gives us into our own kind.
+func parseImages(summaries []dockerTypes.ImageSummary) []types.Image {
+	images := []types.Image{}
+
+	for _, summary := range summaries {
+		images = append(images, types.Image{
+			Id:        summary.ID,
+			Tags:      summary.RepoTags,
+			Digests:   summary.RepoDigests,
+			Size:      summary.Size,
+			CreatedAt: time.Unix(summary.Created, 0),
+		})
+	}
+
+	return images
+}
@@ -0,0 +1,82 @@
+package docker
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/4khara/replica"
+	dockerTypes "github.com/docker/docker/api/types"
+)
+
+func mockPullStream(messages ...string) io.ReadCloser {
+	return io.NopCloser(strings.NewReader(strings.Join(messages, "\n")))
+}
+
+func TestPullOnlyReportsWhenALayerChanges(t *testing.T) {
+	before()
+
+	replica.Mocks.SetReturnValues("Pull", mockPullStream(
+		`{"status":"Pulling from library/nginx","id":"latest"}`,
+		`{"status":"Downloading","id":"a1","progressDetail":{"current":10,"total":100}}`,
+		`{"status":"Downloading","id":"a1","progressDetail":{"current":50,"total":100}}`,
+		`{"status":"Pull complete","id":"a1"}`,
+		`{"status":"Status: Downloaded newer image for nginx:latest"}`,
+	), nil)
+
+	lines := []string{}
+	err := Pull("nginx", func(line string) {
+		lines = append(lines, line)
+	})
+
+	if err != nil {
+		t.Fatal("Expected no error, got", err)
+	}
+
+	expected := []string{
+		"latest: Pulling from library/nginx",
+		"a1: Downloading",
+		"a1: Pull complete",
+		"Status: Downloaded newer image for nginx:latest",
+	}
+
+	if strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Error("Pull should report every change once, got", lines)
+	}
+}
+
+func TestPullReturnsTheErrorsDockerSends(t *testing.T) {
+	before()
+
+	replica.Mocks.SetReturnValues("Pull", mockPullStream(
+		`{"status":"Pulling from library/nope","id":"latest"}`,
+		`{"errorDetail":{"message":"manifest unknown"},"error":"manifest unknown"}`,
+	), nil)
+
+	err := Pull("nope", func(line string) {})
+
+	if err == nil || err.Error() != "manifest unknown" {
+		t.Error("Pull should return the error from the stream, got", err)
+	}
+}
+
+func TestImagesAreParsed(t *testing.T) {
+	before()
+
+	replica.Mocks.SetReturnValues("Images", []dockerTypes.ImageSummary{
+		{ID: "sha256:abc", RepoTags: []string{"nginx:latest"}, Size: 1024, Created: 1600000000},
+	}, nil)
+
+	images, err := Images()
+	if err != nil {
+		t.Fatal("Expected no error, got", err)
+	}
+
+	if len(images) != 1 || images[0].Id != "sha256:abc" || images[0].Size != 1024 {
+		t.Fatal("Images should return the local images, got", images)
+	}
+
+	if images[0].CreatedAt.Unix() != 1600000000 {
+		t.Error("Images should keep the creation time, got", images[0].CreatedAt)
+	}
+}
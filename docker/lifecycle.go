@@ -0,0 +1,134 @@
+package docker
+
+import (
+	"co2/types"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// Returned whenever docker doesn't know about the requested container.
+var ErrNotFound = errors.New("container not found")
+
+// Options for reading the logs of a container.
+type LogOptions struct {
+	Follow     bool   // Keep streaming new lines until the container stops
+	Tail       string // Number of lines to show from the end, or "all"
+	Since      string // Only show logs after this timestamp or relative duration
+	Until      string // Only show logs before this timestamp or relative duration
+	Timestamps bool   // Prefix every line with the time docker received it
+}
+
+// Inspects the given container and returns its current state.
+//
+// The container can be referenced either by its name or by
+// the ID docker gave it.
+func Inspect(container string) (types.ContainerState, error) {
+	return inspectOn(defaultHost, container)
+}
+
+// Inspects the given container on the given docker host.
+func inspectOn(host string, container string) (types.ContainerState, error) {
+	inspected, err := on(host).Inspect(container)
+	if err != nil {
+		return types.ContainerState{}, notFound(err)
+	}
+
+	if inspected.ContainerJSONBase == nil || inspected.State == nil {
+		return types.ContainerState{}, ErrNotFound
+	}
+
+	state := inspected.State
+	parsed := types.ContainerState{
+		Status:     state.Status,
+		Running:    state.Running,
+		ExitCode:   state.ExitCode,
+		OOMKilled:  state.OOMKilled,
+		StartedAt:  timestamp(state.StartedAt),
+		FinishedAt: timestamp(state.FinishedAt),
+	}
+
+	if state.Health != nil {
+		parsed.Health = state.Health.Status
+	}
+
+	return parsed, nil
+}
+
+// Removes the given container. If force is set, the container
+// will be killed first if it's still running.
+func Remove(container string, force bool) error {
+	return removeOn(defaultHost, container, force)
+}
+
+// Removes the given container on the given docker host.
+func removeOn(host string, container string, force bool) error {
+	return notFound(on(host).Remove(container, dockerTypes.ContainerRemoveOptions{
+		Force: force,
+	}))
+}
+
+// Streams the logs of the given container on the given docker
+// host into the given writers.
+//
+// Docker multiplexes stdout and stderr into a single stream unless the
+// container was started with a TTY, so this checks which of the two it's
+// dealing with before splitting them back up. If following, this only
+// returns once the container stops or the stream breaks.
+func logsOn(host string, container string, options LogOptions, stdout, stderr io.Writer) error {
+	cli := on(host)
+
+	inspected, err := cli.Inspect(container)
+	if err != nil {
+		return notFound(err)
+	}
+
+	stream, err := cli.Logs(container, dockerTypes.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     options.Follow,
+		Tail:       options.Tail,
+		Since:      options.Since,
+		Until:      options.Until,
+		Timestamps: options.Timestamps,
+	})
+	if err != nil {
+		return notFound(err)
+	}
+	defer stream.Close()
+
+	if inspected.Config != nil && inspected.Config.Tty {
+		_, err = io.Copy(stdout, stream)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, stream)
+	}
+
+	return err
+}
+
+// Replaces the not found errors coming from the docker client with
+// our own so that callers don't need to know about the client at all.
+func notFound(err error) error {
+	if err != nil && client.IsErrNotFound(err) {
+		return ErrNotFound
+	}
+
+	return err
+}
+
+// Parses the timestamps docker uses for container states. Docker
+// reports containers that never started or finished with the zero time,
+// so anything unparsable is treated the same way.
+func timestamp(value string) time.Time {
+	parsed, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}
+	}
+
+	return parsed
+}
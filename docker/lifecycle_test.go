@@ -0,0 +1,136 @@
+package docker
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/4khara/replica"
+	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+type notFoundError struct{}
+
+func (e notFoundError) Error() string { return "No such container" }
+func (e notFoundError) NotFound()     {}
+
+func TestInspectParsesState(t *testing.T) {
+	before()
+
+	replica.Mocks.SetReturnValues("Inspect", dockerTypes.ContainerJSON{
+		ContainerJSONBase: &dockerTypes.ContainerJSONBase{
+			State: &dockerTypes.ContainerState{
+				Status:     "exited",
+				ExitCode:   137,
+				OOMKilled:  true,
+				StartedAt:  "2022-01-02T10:00:00.123456789Z",
+				FinishedAt: "0001-01-01T00:00:00Z",
+				Health:     &dockerTypes.Health{Status: "unhealthy"},
+			},
+		},
+	}, nil)
+
+	state, err := Inspect("container1")
+	if err != nil {
+		t.Fatal("Expected no error, got", err)
+	}
+
+	if state.Status != "exited" || state.Running {
+		t.Error("Expected an exited container, got", state.Status)
+	}
+
+	if state.ExitCode != 137 || !state.OOMKilled {
+		t.Error("Expected exit code 137 and an OOM kill, got", state.ExitCode, state.OOMKilled)
+	}
+
+	if state.Health != "unhealthy" {
+		t.Error("Expected health to be unhealthy, got", state.Health)
+	}
+
+	if state.StartedAt.Year() != 2022 {
+		t.Error("Expected the start time to be parsed, got", state.StartedAt)
+	}
+
+	if !state.FinishedAt.IsZero() {
+		t.Error("Expected the finish time to be zero, got", state.FinishedAt)
+	}
+}
+
+func TestInspectTranslatesNotFound(t *testing.T) {
+	before()
+
+	replica.Mocks.SetReturnValues("Inspect", dockerTypes.ContainerJSON{}, notFoundError{})
+
+	_, err := Inspect("container1")
+
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("Expected ErrNotFound, got", err)
+	}
+}
+
+func TestRemovePassesForce(t *testing.T) {
+	before()
+
+	Remove("container1", true)
+
+	options := replica.Mocks.GetCallParams("Remove")[0][1].(dockerTypes.ContainerRemoveOptions)
+
+	if !options.Force {
+		t.Error("Expected the removal to be forced")
+	}
+}
+
+func TestRemoveReturnsNotFound(t *testing.T) {
+	before()
+
+	replica.Mocks.SetReturnValues("Remove", notFoundError{})
+
+	if err := removeOn("tcp://remote:2375", "container1", false); err != ErrNotFound {
+		t.Error("Expected ErrNotFound, got", err)
+	}
+}
+
+func TestLogsDemultiplexesStreams(t *testing.T) {
+	before()
+
+	var raw bytes.Buffer
+	stdcopy.NewStdWriter(&raw, stdcopy.Stdout).Write([]byte("out\n"))
+	stdcopy.NewStdWriter(&raw, stdcopy.Stderr).Write([]byte("err\n"))
+
+	replica.Mocks.SetReturnValues("Logs", io.NopCloser(&raw), nil)
+
+	var stdout, stderr bytes.Buffer
+	if err := logsOn("", "container1", LogOptions{Tail: "10"}, &stdout, &stderr); err != nil {
+		t.Fatal("Expected no error, got", err)
+	}
+
+	if stdout.String() != "out\n" || stderr.String() != "err\n" {
+		t.Errorf("Expected streams to be split, got %q and %q", stdout.String(), stderr.String())
+	}
+
+	options := replica.Mocks.GetCallParams("Logs")[0][1].(dockerTypes.ContainerLogsOptions)
+
+	if options.Tail != "10" || !options.ShowStdout || !options.ShowStderr {
+		t.Error("Expected log options to be passed through, got", options)
+	}
+}
+
+func TestLogsCopiesTtyStreamsAsIs(t *testing.T) {
+	before()
+
+	replica.Mocks.SetReturnValues("Inspect", dockerTypes.ContainerJSON{
+		ContainerJSONBase: &dockerTypes.ContainerJSONBase{State: &dockerTypes.ContainerState{}},
+		Config:            &container.Config{Tty: true},
+	}, nil)
+	replica.Mocks.SetReturnValues("Logs", io.NopCloser(bytes.NewBufferString("plain\n")), nil)
+
+	var stdout, stderr bytes.Buffer
+	logsOn("", "container1", LogOptions{}, &stdout, &stderr)
+
+	if stdout.String() != "plain\n" {
+		t.Errorf("Expected the raw stream to be copied, got %q", stdout.String())
+	}
+}
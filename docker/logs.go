@@ -0,0 +1,120 @@
+package docker
+
+import (
+	"bufio"
+	"co2/types"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// How the archived logs of every run are named, sortable
+// and without anything some file systems don't allow.
+const ArchiveLayout = "2006-01-02T15-04-05Z"
+
+// Streams the logs of the given container on the given docker host,
+// empty for the default one, line by line into the given channel,
+// along with the time docker received each of them.
+//
+// Docker is always asked for the times, but the lines only keep them
+// in front if the options ask for timestamps. Lines that come with no
+// time of their own get the time of the line before them.
+func StreamLogs(host, container string, options LogOptions, lines chan<- types.LogLine) error {
+	shown := options.Timestamps
+	options.Timestamps = true
+
+	reader, writer := io.Pipe()
+	defer reader.Close()
+
+	go func() {
+		writer.CloseWithError(logsOn(host, container, options, writer, writer))
+	}()
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	previous := time.Time{}
+
+	for scanner.Scan() {
+		line := parseLogLine(scanner.Text(), previous)
+		previous = line.Time
+
+		if shown {
+			line.Text = scanner.Text()
+		}
+
+		lines <- line
+	}
+
+	return scanner.Err()
+}
+
+// Splits a line docker timestamped into the time and the line
+// itself. If there's no time in front, the given one is used instead.
+func parseLogLine(raw string, previous time.Time) types.LogLine {
+	parts := strings.SplitN(raw, " ", 2)
+
+	received, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return types.LogLine{Time: previous, Text: raw}
+	}
+
+	text := ""
+	if len(parts) == 2 {
+		text = parts[1]
+	}
+
+	return types.LogLine{Time: received, Text: text}
+}
+
+// Writes all the logs of the given container on the given docker
+// host, empty for the default one, into a file within the given
+// directory, named after when the container was last started. Every
+// line keeps the time docker received it in front.
+//
+// The path of the file is returned, or nothing at all if the
+// container never started, since then there's nothing to keep.
+func ArchiveLogs(host, container, dir string) (string, error) {
+	state, err := inspectOn(host, container)
+	if err != nil {
+		return "", err
+	}
+
+	if state.StartedAt.IsZero() {
+		return "", nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	name := state.StartedAt.UTC().Format(ArchiveLayout) + ".log"
+
+	// Written next to where it goes and only moved there once it's
+	// complete, so a broken stream never looks like an entire run.
+	temp, err := os.CreateTemp(dir, "."+name+".*")
+	if err != nil {
+		return "", err
+	}
+
+	// Doesn't do anything if the rename succeeded
+	defer os.Remove(temp.Name())
+
+	err = logsOn(host, container, LogOptions{Timestamps: true}, temp, temp)
+	if closed := temp.Close(); err == nil {
+		err = closed
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, name)
+	if err := os.Rename(temp.Name(), path); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
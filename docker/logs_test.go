@@ -0,0 +1,167 @@
+package docker
+
+import (
+	"bytes"
+	"co2/types"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/4khara/replica"
+	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+func streamed(options LogOptions) []types.LogLine {
+	lines := make(chan types.LogLine, 10)
+
+	StreamLogs("", "container1", options, lines)
+	close(lines)
+
+	collected := []types.LogLine{}
+	for line := range lines {
+		collected = append(collected, line)
+	}
+
+	return collected
+}
+
+func TestStreamLogsParsesTheTimes(t *testing.T) {
+	before()
+
+	var raw bytes.Buffer
+	stdcopy.NewStdWriter(&raw, stdcopy.Stdout).Write([]byte("2022-01-01T10:00:00.5Z first\n"))
+	stdcopy.NewStdWriter(&raw, stdcopy.Stderr).Write([]byte("2022-01-01T10:00:01Z second\n"))
+
+	replica.Mocks.SetReturnValues("Logs", io.NopCloser(&raw), nil)
+
+	lines := streamed(LogOptions{})
+
+	if len(lines) != 2 {
+		t.Fatal("Expected both streams to be read, got", lines)
+	}
+
+	if lines[0].Text != "first" || !lines[0].Time.Equal(time.Date(2022, 1, 1, 10, 0, 0, 5e8, time.UTC)) {
+		t.Error("Expected the time to be split from the line, got", lines[0])
+	}
+
+	if lines[1].Text != "second" {
+		t.Error("Expected stderr to be read as well, got", lines[1])
+	}
+
+	options := replica.Mocks.GetCallParams("Logs")[0][1].(dockerTypes.ContainerLogsOptions)
+
+	if !options.Timestamps {
+		t.Error("Expected docker to always be asked for the times")
+	}
+}
+
+func TestStreamLogsKeepsTheTimesWhenAsked(t *testing.T) {
+	before()
+
+	var raw bytes.Buffer
+	stdcopy.NewStdWriter(&raw, stdcopy.Stdout).Write([]byte("2022-01-01T10:00:00Z first\n"))
+
+	replica.Mocks.SetReturnValues("Logs", io.NopCloser(&raw), nil)
+
+	lines := streamed(LogOptions{Timestamps: true})
+
+	if len(lines) != 1 || lines[0].Text != "2022-01-01T10:00:00Z first" {
+		t.Error("Expected the time to stay in front of the line, got", lines)
+	}
+}
+
+func TestParseLogLineFallsBackToThePreviousTime(t *testing.T) {
+	previous := time.Date(2022, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	line := parseLogLine("no time here", previous)
+
+	if !line.Time.Equal(previous) || line.Text != "no time here" {
+		t.Error("Expected the previous time and the whole line, got", line)
+	}
+}
+
+func TestArchiveLogsWritesThemNamedAfterTheStart(t *testing.T) {
+	before()
+
+	replica.Mocks.SetReturnValues("Inspect", dockerTypes.ContainerJSON{
+		ContainerJSONBase: &dockerTypes.ContainerJSONBase{
+			State: &dockerTypes.ContainerState{StartedAt: "2022-01-02T10:00:00.123Z"},
+		},
+	}, nil)
+
+	var raw bytes.Buffer
+	stdcopy.NewStdWriter(&raw, stdcopy.Stdout).Write([]byte("2022-01-02T10:00:01Z out\n"))
+	stdcopy.NewStdWriter(&raw, stdcopy.Stderr).Write([]byte("2022-01-02T10:00:02Z err\n"))
+
+	replica.Mocks.SetReturnValues("Logs", io.NopCloser(&raw), nil)
+
+	dir := filepath.Join(t.TempDir(), "service1")
+
+	path, err := ArchiveLogs("", "container1", dir)
+	if err != nil {
+		t.Fatal("Expected no error, got", err)
+	}
+
+	if path != filepath.Join(dir, "2022-01-02T10-00-00Z.log") {
+		t.Error("Expected the file to be named after the start, got", path)
+	}
+
+	contents, _ := os.ReadFile(path)
+
+	if string(contents) != "2022-01-02T10:00:01Z out\n2022-01-02T10:00:02Z err\n" {
+		t.Errorf("Expected both streams with their times, got %q", string(contents))
+	}
+}
+
+func TestArchiveLogsSkipsContainersThatNeverStarted(t *testing.T) {
+	before()
+
+	replica.Mocks.SetReturnValues("Inspect", dockerTypes.ContainerJSON{
+		ContainerJSONBase: &dockerTypes.ContainerJSONBase{
+			State: &dockerTypes.ContainerState{StartedAt: "0001-01-01T00:00:00Z"},
+		},
+	}, nil)
+
+	dir := t.TempDir()
+
+	path, err := ArchiveLogs("", "container1", dir)
+
+	if path != "" || err != nil {
+		t.Error("Expected nothing to be archived, got", path, err)
+	}
+
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Error("Expected no files to be written, got", entries)
+	}
+}
+
+func TestArchiveLogsLeavesNothingBehindWhenTheStreamBreaks(t *testing.T) {
+	before()
+
+	replica.Mocks.SetReturnValues("Inspect", dockerTypes.ContainerJSON{
+		ContainerJSONBase: &dockerTypes.ContainerJSONBase{
+			State: &dockerTypes.ContainerState{StartedAt: "2022-01-02T10:00:00Z"},
+		},
+	}, nil)
+
+	var raw bytes.Buffer
+	stdcopy.NewStdWriter(&raw, stdcopy.Stdout).Write([]byte("2022-01-02T10:00:01Z out\n"))
+	broken := io.MultiReader(&raw, iotest.ErrReader(errors.New("connection reset")))
+
+	replica.Mocks.SetReturnValues("Logs", io.NopCloser(broken), nil)
+
+	dir := t.TempDir()
+
+	if _, err := ArchiveLogs("", "container1", dir); err == nil {
+		t.Error("Expected the broken stream to fail the archive")
+	}
+
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Error("Expected nothing to be left behind, got", entries)
+	}
+}
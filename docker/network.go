@@ -0,0 +1,104 @@
+package docker
+
+import (
+	"co2/types"
+	"os"
+	"sort"
+	"strings"
+
+	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+// The network all carbon services share unless
+// another one is asked for.
+const defaultNetwork = "carbon"
+
+// The name of the network every carbon service gets attached
+// to, so services started separately can still reach each other.
+//
+// It can be changed with `UseNetwork`, or with the `CO2_NETWORK`
+// environment variable.
+func SharedNetwork() string {
+	if sharedNetwork != "" {
+		return sharedNetwork
+	}
+
+	if name := os.Getenv("CO2_NETWORK"); name != "" {
+		return name
+	}
+
+	return defaultNetwork
+}
+
+// Makes sure the given network exists, creating it as a
+// plain bridge network if it doesn't.
+//
+// A network that already exists is left the way it is, no
+// matter who created it, so any network can be shared.
+func EnsureNetwork(name string) error {
+	_, err := wrapper().docker.NetworkInspect(name)
+	if err == nil {
+		return nil
+	}
+
+	if !client.IsErrNotFound(err) {
+		return err
+	}
+
+	return wrapper().docker.NetworkCreate(name, dockerTypes.NetworkCreate{
+		Driver:         "bridge",
+		CheckDuplicate: true,
+		Labels:         map[string]string{types.LabelNetwork: "true"},
+	})
+}
+
+// Returns all the networks the given container is attached to,
+// sorted by their name, along with how it can be reached in each.
+//
+// Docker always adds the short ID of the container to its aliases,
+// which nobody ever uses to reach it, so that one is left out.
+func Networks(container string) ([]types.ContainerNetwork, error) {
+	inspected, err := wrapper().docker.Inspect(container)
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	networks := []types.ContainerNetwork{}
+	if inspected.NetworkSettings == nil {
+		return networks, nil
+	}
+
+	for name, endpoint := range inspected.NetworkSettings.Networks {
+		if endpoint == nil {
+			continue
+		}
+
+		network := types.ContainerNetwork{
+			Name:    name,
+			Id:      endpoint.NetworkID,
+			IP:      endpoint.IPAddress,
+			Aliases: []string{},
+		}
+
+		if network.IP == "" {
+			network.IP = endpoint.GlobalIPv6Address
+		}
+
+		for _, alias := range endpoint.Aliases {
+			if inspected.ContainerJSONBase != nil && strings.HasPrefix(inspected.ID, alias) {
+				continue
+			}
+
+			network.Aliases = append(network.Aliases, alias)
+		}
+
+		networks = append(networks, network)
+	}
+
+	sort.Slice(networks, func(i, j int) bool {
+		return networks[i].Name < networks[j].Name
+	})
+
+	return networks, nil
+}
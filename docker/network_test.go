@@ -0,0 +1,116 @@
+package docker
+
+import (
+	"co2/types"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/4khara/replica"
+	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+)
+
+func TestNetworksAreSortedAndLeaveOutTheContainerId(t *testing.T) {
+	before()
+
+	replica.Mocks.SetReturnValues("Inspect", dockerTypes.ContainerJSON{
+		ContainerJSONBase: &dockerTypes.ContainerJSONBase{ID: "0123456789abcdef"},
+		NetworkSettings: &dockerTypes.NetworkSettings{
+			Networks: map[string]*network.EndpointSettings{
+				"zeta_default": {NetworkID: "z", IPAddress: "172.20.0.2", Aliases: []string{"api", "0123456789ab"}},
+				"alpha":        {NetworkID: "a", GlobalIPv6Address: "fd00::2"},
+			},
+		},
+	}, nil)
+
+	networks, err := Networks("container1")
+	if err != nil {
+		t.Fatal("Expected no error, got", err)
+	}
+
+	if len(networks) != 2 || networks[0].Name != "alpha" || networks[1].Name != "zeta_default" {
+		t.Fatal("Networks should return every network sorted by name, got", networks)
+	}
+
+	if networks[0].IP != "fd00::2" {
+		t.Error("Networks should fall back to the IPv6 address, got", networks[0].IP)
+	}
+
+	if len(networks[1].Aliases) != 1 || networks[1].Aliases[0] != "api" || networks[1].IP != "172.20.0.2" {
+		t.Error("Networks should leave out the container id alias, got", networks[1])
+	}
+}
+
+func TestNetworksOfMissingContainers(t *testing.T) {
+	before()
+
+	replica.Mocks.SetReturnValues("Inspect", dockerTypes.ContainerJSON{}, notFoundError{})
+
+	if _, err := Networks("nope"); err != ErrNotFound {
+		t.Error("Expected ErrNotFound, got", err)
+	}
+}
+
+func TestSharedNetworkCanBeChanged(t *testing.T) {
+	os.Unsetenv("CO2_NETWORK")
+	if name := SharedNetwork(); name != "carbon" {
+		t.Error("Expected the default network to be carbon, got", name)
+	}
+
+	os.Setenv("CO2_NETWORK", "team")
+	defer os.Unsetenv("CO2_NETWORK")
+
+	if name := SharedNetwork(); name != "team" {
+		t.Error("Expected the network from the environment, got", name)
+	}
+
+	UseNetwork("other")
+	defer UseNetwork("")
+
+	if name := SharedNetwork(); name != "other" {
+		t.Error("Expected the network that was asked for, got", name)
+	}
+}
+
+func TestEnsureNetworkLeavesExistingNetworksAlone(t *testing.T) {
+	before()
+
+	if err := EnsureNetwork("carbon"); err != nil {
+		t.Fatal("Expected no error, got", err)
+	}
+
+	if replica.Mocks.GetCallCount("NetworkCreate") != 0 {
+		t.Error("EnsureNetwork shouldn't create networks that exist")
+	}
+}
+
+func TestEnsureNetworkCreatesMissingNetworks(t *testing.T) {
+	before()
+
+	replica.Mocks.SetReturnValues("NetworkInspect", dockerTypes.NetworkResource{}, notFoundError{})
+
+	if err := EnsureNetwork("carbon"); err != nil {
+		t.Fatal("Expected no error, got", err)
+	}
+
+	params := replica.Mocks.GetCallParams("NetworkCreate")
+	if len(params) != 1 || params[0][0] != "carbon" {
+		t.Fatal("EnsureNetwork should create the network, got", params)
+	}
+
+	options := params[0][1].(dockerTypes.NetworkCreate)
+	if options.Driver != "bridge" || options.Labels[types.LabelNetwork] != "true" {
+		t.Error("EnsureNetwork should create a labelled bridge network, got", options)
+	}
+}
+
+func TestEnsureNetworkReturnsOtherErrors(t *testing.T) {
+	before()
+
+	replica.Mocks.SetReturnValues("NetworkInspect", dockerTypes.NetworkResource{}, errors.New("daemon down"))
+
+	if err := EnsureNetwork("carbon"); err == nil || err.Error() != "daemon down" {
+		t.Error("EnsureNetwork should return the error, got", err)
+	}
+}
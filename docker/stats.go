@@ -0,0 +1,75 @@
+package docker
+
+import (
+	"co2/types"
+	"strings"
+
+	dockerTypes "github.com/docker/docker/api/types"
+)
+
+// Samples the resource usage of the given container.
+//
+// The calculations are the same ones the docker cli uses
+// for `docker stats`, so the numbers should match up.
+func Stats(container string) (types.ContainerStats, error) {
+	raw, err := wrapper().docker.Stats(container)
+	if err != nil {
+		return types.ContainerStats{}, notFound(err)
+	}
+
+	stats := types.ContainerStats{
+		CPUPercent:  cpuPercent(raw),
+		MemoryUsage: memoryUsage(raw.MemoryStats),
+		MemoryLimit: raw.MemoryStats.Limit,
+	}
+
+	for _, network := range raw.Networks {
+		stats.NetworkRx += network.RxBytes
+		stats.NetworkTx += network.TxBytes
+	}
+
+	for _, entry := range raw.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			stats.BlockRead += entry.Value
+		case "write":
+			stats.BlockWrite += entry.Value
+		}
+	}
+
+	return stats, nil
+}
+
+// Works out how much cpu time the container used between
+// the previous sample and this one, compared to the whole system.
+func cpuPercent(stats dockerTypes.StatsJSON) float64 {
+	container := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	system := float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+
+	cpus := float64(stats.CPUStats.OnlineCPUs)
+	if cpus == 0 {
+		cpus = float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+	}
+
+	if container <= 0 || system <= 0 {
+		return 0
+	}
+
+	return container / system * cpus * 100
+}
+
+// Docker counts the page cache as used memory, which makes
+// everything look a lot hungrier than it is. Cgroups v1 and v2
+// name it differently so both are checked.
+func memoryUsage(stats dockerTypes.MemoryStats) uint64 {
+	cache, ok := stats.Stats["total_inactive_file"]
+	if !ok {
+		cache = stats.Stats["inactive_file"]
+	}
+
+	if cache > stats.Usage {
+		return stats.Usage
+	}
+
+	return stats.Usage - cache
+}
@@ -0,0 +1,70 @@
+package docker
+
+import (
+	"math"
+	"testing"
+
+	"github.com/4khara/replica"
+	dockerTypes "github.com/docker/docker/api/types"
+)
+
+func TestStatsCalculatesUsage(t *testing.T) {
+	before()
+
+	raw := dockerTypes.StatsJSON{
+		Networks: map[string]dockerTypes.NetworkStats{
+			"eth0": {RxBytes: 100, TxBytes: 10},
+			"eth1": {RxBytes: 50, TxBytes: 5},
+		},
+	}
+	raw.CPUStats.CPUUsage.TotalUsage = 300
+	raw.CPUStats.SystemUsage = 2000
+	raw.CPUStats.OnlineCPUs = 4
+	raw.PreCPUStats.CPUUsage.TotalUsage = 200
+	raw.PreCPUStats.SystemUsage = 1000
+	raw.MemoryStats = dockerTypes.MemoryStats{
+		Usage: 1000,
+		Limit: 4000,
+		Stats: map[string]uint64{"inactive_file": 200},
+	}
+	raw.BlkioStats.IoServiceBytesRecursive = []dockerTypes.BlkioStatEntry{
+		{Op: "Read", Value: 30},
+		{Op: "write", Value: 40},
+		{Op: "Total", Value: 70},
+	}
+
+	replica.Mocks.SetReturnValues("Stats", raw, nil)
+
+	stats, err := Stats("container1")
+	if err != nil {
+		t.Fatal("Expected no error, got", err)
+	}
+
+	if math.Abs(stats.CPUPercent-40) > 0.001 {
+		t.Error("Expected 40% cpu, got", stats.CPUPercent)
+	}
+
+	if stats.MemoryUsage != 800 || stats.MemoryPercent() != 20 {
+		t.Error("Expected the page cache to be left out of the memory usage, got", stats.MemoryUsage)
+	}
+
+	if stats.NetworkRx != 150 || stats.NetworkTx != 15 {
+		t.Error("Expected all networks to be added up, got", stats.NetworkRx, stats.NetworkTx)
+	}
+
+	if stats.BlockRead != 30 || stats.BlockWrite != 40 {
+		t.Error("Expected block reads and writes to be counted, got", stats.BlockRead, stats.BlockWrite)
+	}
+}
+
+func TestStatsWithoutPreviousSampleHasNoCpuUsage(t *testing.T) {
+	before()
+
+	replica.Mocks.SetReturnValues("Stats", dockerTypes.StatsJSON{}, nil)
+
+	stats, _ := Stats("container1")
+
+	if stats.CPUPercent != 0 {
+		t.Error("Expected no cpu usage without a previous sample, got", stats.CPUPercent)
+	}
+}
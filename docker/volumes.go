@@ -0,0 +1,193 @@
+package docker
+
+import (
+	"co2/types"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/client"
+)
+
+// The image of the short lived containers that get access to
+// volumes for us, since volumes can only be reached through one.
+const helperImage = "busybox:latest"
+
+// Where volumes are mounted within the helper containers.
+const helperMount = "/volume"
+
+// Returns every volume that's mounted into the containers with the
+// given labels, running or not, sorted by service and then by name.
+//
+// A volume that's mounted into multiple containers shows up once
+// for every one of them.
+func Volumes(labels ...string) ([]types.Volume, error) {
+	containers, err := wrapper().docker.AllContainers(labelFilters(labels))
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := wrapper().docker.DiskUsage()
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := map[string]int64{}
+	for _, volume := range usage.Volumes {
+		if volume != nil && volume.UsageData != nil {
+			sizes[volume.Name] = volume.UsageData.Size
+		}
+	}
+
+	volumes := []types.Volume{}
+
+	for _, container := range containers {
+		for _, mounted := range container.Mounts {
+			if mounted.Type != mount.TypeVolume {
+				continue
+			}
+
+			size, ok := sizes[mounted.Name]
+			if !ok {
+				size = -1
+			}
+
+			volumes = append(volumes, types.Volume{
+				Name:        mounted.Name,
+				Destination: mounted.Destination,
+				Anonymous:   anonymous(mounted.Name),
+				Size:        size,
+				Container:   strings.TrimPrefix(container.Names[0], "/"),
+				Service:     container.Labels[types.LabelService],
+				Running:     container.State == "running",
+			})
+		}
+	}
+
+	sort.Slice(volumes, func(i, j int) bool {
+		if volumes[i].Service != volumes[j].Service {
+			return volumes[i].Service < volumes[j].Service
+		}
+
+		return volumes[i].Name < volumes[j].Name
+	})
+
+	return volumes, nil
+}
+
+// Removes the given volume, as long as nothing is using it.
+func RemoveVolume(name string) error {
+	return wrapper().docker.VolumeRemove(name, false)
+}
+
+// Writes everything within the given volume into a tar
+// archive at the given path.
+func BackupVolume(volume string, dst string) error {
+	helper, err := volumeHelper(volume, nil)
+	if err != nil {
+		return err
+	}
+	defer removeHelper(helper)
+
+	stream, _, err := wrapper().docker.CopyFrom(helper, helperMount)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	file, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(file, stream); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// Replaces everything within the given volume with what's in
+// the given archive, which has to be one written by BackupVolume().
+//
+// The volume is emptied first so that it ends up exactly the way
+// it was when it was backed up.
+func RestoreVolume(volume string, src string) error {
+	file, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	helper, err := volumeHelper(volume, []string{"find", helperMount, "-mindepth", "1", "-delete"})
+	if err != nil {
+		return err
+	}
+	defer removeHelper(helper)
+
+	if err := wrapper().docker.Start(helper); err != nil {
+		return err
+	}
+
+	code, err := wrapper().docker.Wait(helper)
+	if err != nil {
+		return err
+	}
+
+	if code != 0 {
+		return fmt.Errorf("couldn't empty the volume, exited with %d", code)
+	}
+
+	return wrapper().docker.CopyTo(helper, "/", file)
+}
+
+// Creates a container with the given volume mounted, that runs
+// the given command if it ever gets started, and returns its ID.
+//
+// The helper image gets pulled first if it isn't there yet.
+func volumeHelper(volume string, command []string) (string, error) {
+	config := &container.Config{
+		Image: helperImage,
+		Cmd:   command,
+	}
+
+	host := &container.HostConfig{
+		Mounts: []mount.Mount{
+			{Type: mount.TypeVolume, Source: volume, Target: helperMount},
+		},
+	}
+
+	id, err := wrapper().docker.Create(config, host)
+	if err == nil || !client.IsErrNotFound(err) {
+		return id, err
+	}
+
+	if err := Pull(helperImage, func(string) {}); err != nil {
+		return "", err
+	}
+
+	return wrapper().docker.Create(config, host)
+}
+
+// Gets rid of a helper container once it's done.
+func removeHelper(id string) {
+	wrapper().docker.Remove(id, dockerTypes.ContainerRemoveOptions{Force: true})
+}
+
+// Docker names volumes that weren't given a name
+// after a random 64 character hex string.
+func anonymous(name string) bool {
+	if len(name) != 64 {
+		return false
+	}
+
+	_, err := hex.DecodeString(name)
+	return err == nil
+}
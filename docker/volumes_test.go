@@ -0,0 +1,127 @@
+package docker
+
+import (
+	"co2/types"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/4khara/replica"
+	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+)
+
+var anonymousVolume = strings.Repeat("ab", 32)
+
+func TestVolumesOfCarbonContainers(t *testing.T) {
+	before()
+
+	replica.Mocks.SetReturnValues("AllContainers", []dockerTypes.Container{
+		{
+			Names:  []string{"/db-1"},
+			State:  "exited",
+			Labels: map[string]string{types.LabelService: "db"},
+			Mounts: []dockerTypes.MountPoint{
+				{Type: mount.TypeVolume, Name: "pgdata", Destination: "/var/lib/postgresql/data"},
+				{Type: mount.TypeBind, Source: "/code", Destination: "/code"},
+			},
+		},
+		{
+			Names:  []string{"/api-1"},
+			State:  "running",
+			Labels: map[string]string{types.LabelService: "api"},
+			Mounts: []dockerTypes.MountPoint{
+				{Type: mount.TypeVolume, Name: anonymousVolume, Destination: "/cache"},
+			},
+		},
+	})
+	replica.Mocks.SetReturnValues("DiskUsage", dockerTypes.DiskUsage{
+		Volumes: []*dockerTypes.Volume{
+			{Name: "pgdata", UsageData: &dockerTypes.VolumeUsageData{Size: 2048}},
+		},
+	}, nil)
+
+	volumes, err := Volumes(types.LabelService)
+	if err != nil {
+		t.Fatal("Expected no error, got", err)
+	}
+
+	if len(volumes) != 2 {
+		t.Fatal("Volumes should leave out everything that isn't a volume, got", volumes)
+	}
+
+	api, db := volumes[0], volumes[1]
+
+	if api.Service != "api" || !api.Anonymous || !api.Running || api.Size != -1 {
+		t.Error("Volumes should describe the anonymous volume, got", api)
+	}
+
+	if db.Name != "pgdata" || db.Anonymous || db.Running || db.Size != 2048 || db.Container != "db-1" {
+		t.Error("Volumes should describe the named volume, got", db)
+	}
+}
+
+func TestBackupVolumeWritesTheArchive(t *testing.T) {
+	before()
+
+	dst := filepath.Join(t.TempDir(), "backup.tar")
+	replica.Mocks.SetReturnValues("CopyFrom", io.NopCloser(strings.NewReader("archive")), dockerTypes.ContainerPathStat{}, nil)
+
+	if err := BackupVolume("pgdata", dst); err != nil {
+		t.Fatal("Expected no error, got", err)
+	}
+
+	contents, _ := os.ReadFile(dst)
+	if string(contents) != "archive" {
+		t.Error("BackupVolume should write what docker sent, got", string(contents))
+	}
+
+	host := replica.Mocks.GetCallParams("Create")[0][1].(*container.HostConfig)
+	if host.Mounts[0].Source != "pgdata" {
+		t.Error("BackupVolume should mount the volume, got", host.Mounts)
+	}
+
+	if replica.Mocks.GetCallCount("Remove") != 1 {
+		t.Error("BackupVolume should remove the helper container")
+	}
+}
+
+func TestRestoreVolumeEmptiesTheVolumeFirst(t *testing.T) {
+	before()
+
+	src := filepath.Join(t.TempDir(), "backup.tar")
+	os.WriteFile(src, []byte("archive"), 0644)
+
+	if err := RestoreVolume("pgdata", src); err != nil {
+		t.Fatal("Expected no error, got", err)
+	}
+
+	if replica.Mocks.GetCallCount("Start") != 1 || replica.Mocks.GetCallCount("Wait") != 1 {
+		t.Error("RestoreVolume should empty the volume before restoring")
+	}
+
+	params := replica.Mocks.GetCallParams("CopyTo")[0]
+	if params[1] != "/" || string(params[2].([]byte)) != "archive" {
+		t.Error("RestoreVolume should copy the archive into the helper, got", params)
+	}
+}
+
+func TestRestoreVolumeStopsWhenTheVolumeCantBeEmptied(t *testing.T) {
+	before()
+
+	src := filepath.Join(t.TempDir(), "backup.tar")
+	os.WriteFile(src, []byte("archive"), 0644)
+
+	replica.Mocks.SetReturnValues("Wait", int64(1), nil)
+
+	if err := RestoreVolume("pgdata", src); err == nil {
+		t.Error("RestoreVolume should fail when the volume can't be emptied")
+	}
+
+	if replica.Mocks.GetCallCount("CopyTo") != 0 {
+		t.Error("RestoreVolume shouldn't restore into a volume that wasn't emptied")
+	}
+}
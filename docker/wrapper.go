@@ -2,31 +2,328 @@ package docker
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"sync"
 
 	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/client"
 )
 
 // Docker API wrapper to allow for easy mocking.
 type DockerWrapper interface {
-	RunningContainers() []dockerTypes.Container
+	RunningContainers(filters filters.Args) ([]dockerTypes.Container, error)
+	AllContainers(filters filters.Args) ([]dockerTypes.Container, error)
+	Inspect(id string) (dockerTypes.ContainerJSON, error)
+	Remove(id string, options dockerTypes.ContainerRemoveOptions) error
+	Logs(id string, options dockerTypes.ContainerLogsOptions) (io.ReadCloser, error)
+	Events(filters filters.Args) (<-chan events.Message, <-chan error)
+	Stats(id string) (dockerTypes.StatsJSON, error)
+	Pull(image string) (io.ReadCloser, error)
+	Images(filters filters.Args) ([]dockerTypes.ImageSummary, error)
+	StatPath(id string, path string) (dockerTypes.ContainerPathStat, error)
+	CopyFrom(id string, path string) (io.ReadCloser, dockerTypes.ContainerPathStat, error)
+	CopyTo(id string, path string, content io.Reader) error
+	NetworkInspect(name string) (dockerTypes.NetworkResource, error)
+	NetworkCreate(name string, options dockerTypes.NetworkCreate) error
+	DiskUsage() (dockerTypes.DiskUsage, error)
+	VolumeRemove(name string, force bool) error
+	Create(config *container.Config, host *container.HostConfig) (string, error)
+	Start(id string) error
+	Wait(id string) (int64, error)
 }
 
-type Wrapper struct{}
+type Wrapper struct {
+	Host string // The docker host to talk to, the default one if empty
+}
+
+// The clients that have already been created, one for every
+// host, so that every call doesn't open up its own connections.
+var (
+	clients      = map[string]*client.Client{}
+	clientsMutex sync.Mutex
+)
+
+// Gets the client for the docker api, configured from the
+// environment the same way the docker cli is. Every host only
+// ever gets one client, which is shared by all the calls.
+//
+// If there's a host set, either on the wrapper itself or as the
+// default, it takes priority over the one from the environment.
+func (w *Wrapper) client() (*client.Client, error) {
+	host := w.Host
+	if host == "" {
+		host = defaultHost
+	}
+
+	clientsMutex.Lock()
+	defer clientsMutex.Unlock()
+
+	if cli, ok := clients[host]; ok {
+		return cli, nil
+	}
+
+	opts := []client.Opt{client.FromEnv, client.WithAPIVersionNegotiation()}
+
+	if host != "" {
+		opts = append(opts, client.WithHost(host))
+	}
+
+	cli, err := client.NewClientWithOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	clients[host] = cli
+	return cli, nil
+}
 
 // Pull the running containers directly from the docker api.
 // We want speed, and that seems to be the fastest option here since
 // docker itself uses this api.
-func (w *Wrapper) RunningContainers() []dockerTypes.Container {
-	cli, err := client.NewClientWithOpts(client.FromEnv)
+//
+// Only the containers matching the given filters are returned.
+func (w *Wrapper) RunningContainers(filters filters.Args) ([]dockerTypes.Container, error) {
+	cli, err := w.client()
+	if err != nil {
+		return nil, err
+	}
+
+	return cli.ContainerList(context.Background(), dockerTypes.ContainerListOptions{
+		Filters: filters,
+	})
+}
+
+// Pull all the containers, running or not, directly from the docker api.
+//
+// Only the containers matching the given filters are returned.
+func (w *Wrapper) AllContainers(filters filters.Args) ([]dockerTypes.Container, error) {
+	cli, err := w.client()
+	if err != nil {
+		return nil, err
+	}
+
+	return cli.ContainerList(context.Background(), dockerTypes.ContainerListOptions{
+		All:     true,
+		Filters: filters,
+	})
+}
+
+// Returns everything docker knows about the given container.
+func (w *Wrapper) Inspect(id string) (dockerTypes.ContainerJSON, error) {
+	cli, err := w.client()
+	if err != nil {
+		return dockerTypes.ContainerJSON{}, err
+	}
+
+	return cli.ContainerInspect(context.Background(), id)
+}
+
+// Removes the given container.
+func (w *Wrapper) Remove(id string, options dockerTypes.ContainerRemoveOptions) error {
+	cli, err := w.client()
+	if err != nil {
+		return err
+	}
+
+	return cli.ContainerRemove(context.Background(), id, options)
+}
+
+// Opens the raw log stream of the given container. The caller is
+// responsible for closing it.
+func (w *Wrapper) Logs(id string, options dockerTypes.ContainerLogsOptions) (io.ReadCloser, error) {
+	cli, err := w.client()
+	if err != nil {
+		return nil, err
+	}
+
+	return cli.ContainerLogs(context.Background(), id, options)
+}
+
+// Subscribes to the events of the docker daemon that match the given
+// filters. The events keep coming until the connection breaks, at
+// which point the error gets sent through the second channel.
+func (w *Wrapper) Events(filters filters.Args) (<-chan events.Message, <-chan error) {
+	cli, err := w.client()
+	if err != nil {
+		errs := make(chan error, 1)
+		errs <- err
+
+		return nil, errs
+	}
+
+	return cli.Events(context.Background(), dockerTypes.EventsOptions{
+		Filters: filters,
+	})
+}
+
+// Takes a single sample of the resource usage of the given container.
+//
+// This isn't a one-shot sample, so docker takes a second to measure
+// the cpu usage since the previous sample, which we need for percentages.
+func (w *Wrapper) Stats(id string) (dockerTypes.StatsJSON, error) {
+	var stats dockerTypes.StatsJSON
+
+	cli, err := w.client()
+	if err != nil {
+		return stats, err
+	}
+
+	res, err := cli.ContainerStats(context.Background(), id, false)
+	if err != nil {
+		return stats, err
+	}
+	defer res.Body.Close()
+
+	err = json.NewDecoder(res.Body).Decode(&stats)
+	return stats, err
+}
+
+// Starts pulling the given image and returns the stream of json
+// progress messages docker sends while it's at it. The pull is only
+// done once the stream ends, and the caller is responsible for closing it.
+//
+// Whatever `docker login` saved for the registry of the image is sent
+// along, so private images pull the same way they do with docker.
+func (w *Wrapper) Pull(image string) (io.ReadCloser, error) {
+	cli, err := w.client()
+	if err != nil {
+		return nil, err
+	}
+
+	return cli.ImagePull(context.Background(), image, dockerTypes.ImagePullOptions{
+		RegistryAuth: registryAuth(image),
+	})
+}
+
+// Lists the images that are present locally and match the given filters.
+func (w *Wrapper) Images(filters filters.Args) ([]dockerTypes.ImageSummary, error) {
+	cli, err := w.client()
+	if err != nil {
+		return nil, err
+	}
+
+	return cli.ImageList(context.Background(), dockerTypes.ImageListOptions{
+		Filters: filters,
+	})
+}
+
+// Describes the given path within the container, without
+// copying anything.
+func (w *Wrapper) StatPath(id string, path string) (dockerTypes.ContainerPathStat, error) {
+	cli, err := w.client()
+	if err != nil {
+		return dockerTypes.ContainerPathStat{}, err
+	}
+
+	return cli.ContainerStatPath(context.Background(), id, path)
+}
+
+// Opens a tar archive of the given path within the container,
+// along with a description of the path. The caller is responsible
+// for closing it.
+func (w *Wrapper) CopyFrom(id string, path string) (io.ReadCloser, dockerTypes.ContainerPathStat, error) {
+	cli, err := w.client()
+	if err != nil {
+		return nil, dockerTypes.ContainerPathStat{}, err
+	}
+
+	return cli.CopyFromContainer(context.Background(), id, path)
+}
+
+// Extracts the given tar archive into the given directory
+// within the container.
+func (w *Wrapper) CopyTo(id string, path string, content io.Reader) error {
+	cli, err := w.client()
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	containers, err := cli.ContainerList(context.Background(), dockerTypes.ContainerListOptions{})
+	return cli.CopyToContainer(context.Background(), id, path, content, dockerTypes.CopyToContainerOptions{})
+}
+
+// Returns everything docker knows about the given network.
+func (w *Wrapper) NetworkInspect(name string) (dockerTypes.NetworkResource, error) {
+	cli, err := w.client()
+	if err != nil {
+		return dockerTypes.NetworkResource{}, err
+	}
+
+	return cli.NetworkInspect(context.Background(), name, dockerTypes.NetworkInspectOptions{})
+}
+
+// Creates a new network with the given name.
+func (w *Wrapper) NetworkCreate(name string, options dockerTypes.NetworkCreate) error {
+	cli, err := w.client()
 	if err != nil {
-		panic(err)
+		return err
 	}
 
-	return containers
+	_, err = cli.NetworkCreate(context.Background(), name, options)
+	return err
+}
+
+// Returns how much space everything docker keeps around takes up,
+// which is the only place docker reports the size of volumes.
+func (w *Wrapper) DiskUsage() (dockerTypes.DiskUsage, error) {
+	cli, err := w.client()
+	if err != nil {
+		return dockerTypes.DiskUsage{}, err
+	}
+
+	return cli.DiskUsage(context.Background())
+}
+
+// Removes the given volume. Docker refuses to remove volumes
+// that are still used by a container unless forced to.
+func (w *Wrapper) VolumeRemove(name string, force bool) error {
+	cli, err := w.client()
+	if err != nil {
+		return err
+	}
+
+	return cli.VolumeRemove(context.Background(), name, force)
+}
+
+// Creates a new container, without starting it, and
+// returns the ID docker gave it.
+func (w *Wrapper) Create(config *container.Config, host *container.HostConfig) (string, error) {
+	cli, err := w.client()
+	if err != nil {
+		return "", err
+	}
+
+	created, err := cli.ContainerCreate(context.Background(), config, host, nil, nil, "")
+	return created.ID, err
+}
+
+// Starts the given container.
+func (w *Wrapper) Start(id string) error {
+	cli, err := w.client()
+	if err != nil {
+		return err
+	}
+
+	return cli.ContainerStart(context.Background(), id, dockerTypes.ContainerStartOptions{})
+}
+
+// Waits for the given container to stop running
+// and returns the exit code it stopped with.
+func (w *Wrapper) Wait(id string) (int64, error) {
+	cli, err := w.client()
+	if err != nil {
+		return -1, err
+	}
+
+	statuses, errs := cli.ContainerWait(context.Background(), id, container.WaitConditionNotRunning)
+
+	select {
+	case status := <-statuses:
+		return status.StatusCode, nil
+	case err := <-errs:
+		return -1, err
+	}
 }
@@ -65,6 +65,25 @@ func DatabaseFile() string {
 	return home + "/.carbon/database.db"
 }
 
+// Generates the path of the file that carbon locks whenever
+// it's about to change any of its state.
+//
+// Lives next to the database, in ~/.carbon, which will be
+// created if it doesn't already exist.
+func LockFile() string {
+	return ComposeDir() + "/carbon.lock"
+}
+
+// Generates the path where the logs of stopped containers
+// are kept, with a directory for each of the services in it.
+//
+// Lives in ~/.carbon, which will be created if it doesn't
+// already exist. The logs directory itself is only created
+// once there's something to put in it.
+func LogsDir() string {
+	return ComposeDir() + "/logs"
+}
+
 // Turns a relative path into an absolute path.
 //
 // Meaning something like `./foo` will be
@@ -89,3 +108,11 @@ func IsDirectory(path string) bool {
 
 	return info.IsDir()
 }
+
+// Checks whether or not anything exists at
+// the provided path, be it a file or a directory.
+func Exists(path string) bool {
+	_, err := os.Stat(ExpandPath(path))
+
+	return err == nil
+}
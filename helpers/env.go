@@ -0,0 +1,48 @@
+package helpers
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// Reads the variables out of an environment file, the same
+// kind docker compose accepts with `--env-file`.
+//
+// Empty lines and comments are skipped, an `export` in front
+// of a variable is ignored, and values can be wrapped in quotes.
+func ReadEnvFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	variables := map[string]string{}
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) == 1 {
+			parts = append(parts, "")
+		}
+
+		key := strings.TrimSpace(parts[0])
+		value := strings.TrimSpace(parts[1])
+
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+
+		variables[key] = value
+	}
+
+	return variables, scanner.Err()
+}
@@ -0,0 +1,41 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadEnvFileParsesVariables(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	os.WriteFile(path, []byte("# comment\n\nTOKEN=abc\nexport NAME=\"carbon co2\"\nQUOTED='single'\nEMPTY=\nURL=http://x?a=b\n"), 0644)
+
+	variables, err := ReadEnvFile(path)
+	if err != nil {
+		t.Fatal("Expected no error, got", err)
+	}
+
+	expected := map[string]string{
+		"TOKEN":  "abc",
+		"NAME":   "carbon co2",
+		"QUOTED": "single",
+		"EMPTY":  "",
+		"URL":    "http://x?a=b",
+	}
+
+	if len(variables) != len(expected) {
+		t.Fatal("Expected", len(expected), "variables, got", variables)
+	}
+
+	for key, value := range expected {
+		if variables[key] != value {
+			t.Errorf("Expected %s to be %q, got %q", key, value, variables[key])
+		}
+	}
+}
+
+func TestReadEnvFileFailsForMissingFiles(t *testing.T) {
+	if _, err := ReadEnvFile(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
@@ -10,13 +10,36 @@ import (
 //
 // It does not add any extensions to the file so it has to be
 // provided in the name. Keep that in mind.
+//
+// The contents are written to a temporary file first which then
+// replaces the real one, so nothing ever sees a half written file
+// even if something else is reading it at the same time.
 func WriteFile(path string, name string, contents []byte) (string, error) {
-	// Create the file
-	err := ioutil.WriteFile(path+"/"+name, contents, 0644)
+	temp, err := ioutil.TempFile(path, "."+name+".*")
 	if err != nil {
 		return "", err
 	}
 
+	// Doesn't do anything if the rename succeeded
+	defer os.Remove(temp.Name())
+
+	if _, err = temp.Write(contents); err != nil {
+		temp.Close()
+		return "", err
+	}
+
+	if err = temp.Close(); err != nil {
+		return "", err
+	}
+
+	if err = os.Chmod(temp.Name(), 0644); err != nil {
+		return "", err
+	}
+
+	if err = os.Rename(temp.Name(), path+"/"+name); err != nil {
+		return "", err
+	}
+
 	return path + "/" + name, nil
 }
 
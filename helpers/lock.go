@@ -0,0 +1,59 @@
+package helpers
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// Returned when the lock is still held by someone else
+// after we've given up waiting for it.
+var ErrLocked = errors.New("the lock is held by another process")
+
+// How long to wait between each attempt at taking the lock.
+const lockRetry = 50 * time.Millisecond
+
+// Takes an exclusive advisory lock on the file at the given path,
+// creating the file if it doesn't exist.
+//
+// If someone else is holding the lock, this will keep retrying until
+// the timeout runs out and then give up with ErrLocked. A timeout of 0
+// means only a single attempt is made.
+//
+// The returned function releases the lock and has to be called once
+// whatever needed the lock is done. The lock is also released by the
+// operating system if the process dies, so nothing ever stays locked.
+func Lock(path string, timeout time.Duration) (func(), error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+
+	for {
+		ok, err := tryLock(file)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+
+		if ok {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			file.Close()
+			return nil, ErrLocked
+		}
+
+		time.Sleep(lockRetry)
+	}
+
+	release := func() {
+		unlock(file)
+		file.Close()
+	}
+
+	return release, nil
+}
@@ -0,0 +1,42 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockCanBeTakenAndReleased(t *testing.T) {
+	path := ComposeDir() + "/test.lock"
+	defer DeleteFile(path)
+
+	unlock, err := Lock(path, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	unlock()
+
+	// Should be free again
+	unlock, err = Lock(path, 0)
+	if err != nil {
+		t.Fatalf("Expected the lock to be free after releasing, got %s", err)
+	}
+
+	unlock()
+}
+
+func TestLockTimesOutWhenAlreadyHeld(t *testing.T) {
+	path := ComposeDir() + "/test.lock"
+	defer DeleteFile(path)
+
+	unlock, err := Lock(path, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	defer unlock()
+
+	_, err = Lock(path, 100*time.Millisecond)
+	if err != ErrLocked {
+		t.Errorf("Expected %s, got %v", ErrLocked, err)
+	}
+}
@@ -0,0 +1,25 @@
+//go:build !windows
+// +build !windows
+
+package helpers
+
+import (
+	"os"
+	"syscall"
+)
+
+// Tries to take the lock once, without blocking.
+// Returns false if someone else is already holding it.
+func tryLock(file *os.File) (bool, error) {
+	err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if err == syscall.EWOULDBLOCK {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// Releases a lock taken with tryLock.
+func unlock(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,33 @@
+//go:build windows
+// +build windows
+
+package helpers
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// Tries to take the lock once, without blocking.
+// Returns false if someone else is already holding it.
+func tryLock(file *os.File) (bool, error) {
+	err := windows.LockFileEx(
+		windows.Handle(file.Fd()),
+		windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY,
+		0,
+		1,
+		0,
+		&windows.Overlapped{},
+	)
+	if err == windows.ERROR_LOCK_VIOLATION {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// Releases a lock taken with tryLock.
+func unlock(file *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, &windows.Overlapped{})
+}
@@ -107,6 +107,14 @@ func Extra(color Color, info ...string) {
 	)
 }
 
+// Prints a plain line of output, without any styling.
+//
+// Useful for output that's styled somewhere else, like
+// the labelled lines of a running command.
+func Ln(a ...interface{}) {
+	out.Ln(a...)
+}
+
 // Generates the styling for the text that should be printed
 // but returns it instead of sending it to stdout.
 //
@@ -44,30 +44,52 @@ func CustomExecutor(e ExecutorInterface) ExecutorInterface {
 //
 // This will stream all the output to the console and end itself
 // when the output channels have been closed.
-func Execute(commands ...types.Command) {
+//
+// The exit codes of the commands are returned in the same order
+// the commands were given in.
+func Execute(commands ...types.Command) []int {
 	executor := Executor()
+	codes := make([]int, len(commands))
+
+	// The executors are done with the output before they return
+	// their exit codes, so both have to be waited for.
 	var wg sync.WaitGroup
+	var exited sync.WaitGroup
 
-	for _, command := range commands {
+	for i, command := range commands {
 		wg.Add(1)
+		exited.Add(1)
 
-		colored := colorize(command)
-		label := fmt.Sprintf(label(command.Label), colored)
+		go func(i int, command types.Command) {
+			defer exited.Done()
 
-		go executor.Execute(&wg, command.Text, label)
+			codes[i] = executor.Execute(&wg, command, Label(command))
+		}(i, command)
 	}
 
 	wg.Wait()
+	exited.Wait()
+
+	return codes
+}
+
+// Returns the colored label that gets shown before each line
+// of output for the given command.
+//
+// This is exported so that anything else that prints output
+// belonging to a command, or a service, can look the same.
+func Label(command types.Command) string {
+	return fmt.Sprintf(label(command.Label), colorize(command))
 }
 
 // Return a colored string that contains the given command name.
-// The string is colored based on the command Text/contents meaning if
-// the command is always the same, the color will also always
-// be the same.
+// The string is colored based on the command Label meaning that
+// anything labelled the same way, no matter what it runs, always
+// gets the same color.
 //
 // To be used in combination with the label() function.
 func colorize(command types.Command) string {
-	hash := helpers.Hash(command.Text, 14)
+	hash := helpers.Hash(command.Label, 14)
 	color := helpers.StringToColor(hash)
 	style := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(color))
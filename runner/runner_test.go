@@ -12,10 +12,16 @@ import (
 
 type MockExecutor struct{}
 
-func (e *MockExecutor) Execute(done *sync.WaitGroup, command string, label string) {
-	replica.MockFn(done, command, label)
+func (e *MockExecutor) Execute(done *sync.WaitGroup, command types.Command, label string) int {
+	_, rv := replica.MockFn(done, command, label)
 
 	done.Done()
+
+	if rv != nil {
+		return rv[0].(int)
+	}
+
+	return 0
 }
 
 func before() {
@@ -42,7 +48,7 @@ func TestColorizeAddsColorOfCommand(t *testing.T) {
 		Label: "lmao",
 	}
 
-	hashed := helpers.Hash(command.Text, 14)
+	hashed := helpers.Hash(command.Label, 14)
 	commandColor := helpers.StringToColor(hashed)
 	coloredCommand := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(commandColor)).
@@ -55,6 +61,15 @@ func TestColorizeAddsColorOfCommand(t *testing.T) {
 	}
 }
 
+func TestColorizeOnlyDependsOnTheLabel(t *testing.T) {
+	first := colorize(types.Command{Text: "docker logs api", Label: "api"})
+	second := colorize(types.Command{Text: "api", Label: "api"})
+
+	if first != second {
+		t.Errorf("Expected the same color for the same label, got %q and %q", first, second)
+	}
+}
+
 func TestExecuteCallsAllProvidedCommands(t *testing.T) {
 	before()
 
@@ -75,3 +90,43 @@ func TestExecuteCallsAllProvidedCommands(t *testing.T) {
 		t.Errorf("Expected %d calls, got %d", len(commands), replica.Mocks.GetCallCount("Execute"))
 	}
 }
+
+func TestLabelIsEmptyWithoutCommandLabel(t *testing.T) {
+	if Label(types.Command{Text: "test"}) != "" {
+		t.Error("Expected an empty label for a command without one")
+	}
+}
+
+func TestExecuteReturnsTheExitCodesOfTheCommands(t *testing.T) {
+	before()
+	replica.Mocks.Clear()
+	replica.Mocks.SetReturnValues("Execute", 3)
+	defer replica.Mocks.Clear()
+
+	codes := Execute(
+		types.Command{Text: "test", Label: "lmao"},
+		types.Command{Text: "test2", Label: "lmao2"},
+	)
+
+	if len(codes) != 2 || codes[0] != 3 || codes[1] != 3 {
+		t.Error("Expected the exit code of every command, got", codes)
+	}
+}
+
+func TestExecuteHandsTheArgumentsToTheExecutor(t *testing.T) {
+	before()
+	replica.Mocks.Clear()
+	defer replica.Mocks.Clear()
+
+	Execute(types.Command{
+		Text:  "echo 'Hello World'",
+		Args:  []string{"echo", "Hello World"},
+		Label: "lmao",
+	})
+
+	args := replica.Mocks.GetCallParams("Execute")[0][1].(types.Command).Args
+
+	if len(args) != 2 || args[1] != "Hello World" {
+		t.Error("Expected the arguments to be passed along untouched, got", args)
+	}
+}
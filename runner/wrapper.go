@@ -1,28 +1,44 @@
 package runner
 
 import (
+	"co2/types"
 	"fmt"
-	"strings"
+	"os"
 	"sync"
 
 	exec "github.com/go-cmd/cmd"
 )
 
 type ExecutorInterface interface {
-	Execute(*sync.WaitGroup, string, string)
+	Execute(*sync.WaitGroup, types.Command, string) int
 }
 
 type executorImpl struct{}
 
-func (e *executorImpl) Execute(done *sync.WaitGroup, command string, label string) {
-	// Split into params
-	params := strings.Split(command, " ")
+// Runs the given command, streaming all of its output with the
+// given label in front of every line, and returns its exit code
+// once it's done. Commands that can't even be started get -1.
+//
+// The arguments are handed over exactly as they are, without
+// going through a shell, so nothing in them needs quoting.
+func (e *executorImpl) Execute(done *sync.WaitGroup, command types.Command, label string) int {
+	args := command.Args
+	if len(args) == 0 {
+		fmt.Println(label, "nothing to run")
+		done.Done()
+
+		return -1
+	}
 
 	opts := exec.Options{
 		Buffered:  false,
 		Streaming: true,
 	}
-	run := exec.NewCmdOptions(opts, params[0], params[1:]...)
+	run := exec.NewCmdOptions(opts, args[0], args[1:]...)
+
+	if len(command.Env) > 0 {
+		run.Env = append(os.Environ(), command.Env...)
+	}
 
 	// Stream output from the command and close when
 	// both channels close.
@@ -37,18 +53,33 @@ func (e *executorImpl) Execute(done *sync.WaitGroup, command string, label strin
 					continue
 				}
 
-				fmt.Println(label, string(out))
+				printLine(command, label, out)
 			case err, ok := <-run.Stderr:
 				if !ok {
 					run.Stderr = nil
 					continue
 				}
 
-				fmt.Println(label, string(err))
+				printLine(command, label, err)
 			}
 		}
 	}(done)
 
 	// Block waiting for command to exit, be stopped, or be killed
-	<-run.Start()
+	status := <-run.Start()
+	if status.Error != nil {
+		fmt.Println(label, status.Error.Error())
+	}
+
+	return status.Exit
+}
+
+// Prints a single line of output of the given command with
+// its label in front, unless the command filters it out.
+func printLine(command types.Command, label string, line string) {
+	if command.Filter != nil && !command.Filter(line) {
+		return
+	}
+
+	fmt.Println(label, line)
 }
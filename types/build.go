@@ -0,0 +1,41 @@
+package types
+
+// How the image of a service gets built, taken from the
+// `build` section of its carbon.yml definition.
+type BuildConfig struct {
+	Context    string            // The directory to build from, relative to the carbon.yml
+	Dockerfile string            // The Dockerfile to use, relative to the context
+	Args       map[string]string // The build arguments the service defines itself
+}
+
+// Compose allows the build section to be either just the path
+// of the context, or a full definition, so both are understood.
+//
+// The build arguments can be either a map or a list of `key=value`
+// strings, the same way labels can.
+func (b *BuildConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var context string
+	if err := unmarshal(&context); err == nil {
+		b.Context = context
+		return nil
+	}
+
+	var full struct {
+		Context    string      `yaml:"context"`
+		Dockerfile string      `yaml:"dockerfile"`
+		Args       interface{} `yaml:"args"`
+	}
+
+	if err := unmarshal(&full); err != nil {
+		return err
+	}
+
+	b.Context = full.Context
+	b.Dockerfile = full.Dockerfile
+
+	if full.Args != nil {
+		b.Args = MergeLabels(full.Args, nil)
+	}
+
+	return nil
+}
@@ -1,15 +1,21 @@
 package types
 
+import (
+	"path/filepath"
+	"strings"
+)
+
 // Single service definition for a carbon.yml file.
 // This is what we care aboout from the things that
 // a user writes in a carbon configuration file.
 type CarbonService struct {
-	Name      string   `yaml:"-"`              // The name of the service
-	Path      string   `yaml:"-"`              // The path where the carbon.yml was found
-	Store     *Store   `yaml:"-"`              // The store this file belongs to
-	Image     string   `yaml:"image"`          // The image of the service
-	Container string   `yaml:"container_name"` // The container name of the service (will be overwritten by us)
-	DependsOn []string `yaml:"depends_on"`     // The services that this service depends on
+	Name      string      `yaml:"-"`              // The name of the service
+	Path      string      `yaml:"-"`              // The path where the carbon.yml was found
+	Store     *Store      `yaml:"-"`              // The store this file belongs to
+	Image     string      `yaml:"image"`          // The image of the service
+	Container string      `yaml:"container_name"` // The container name of the service (will be overwritten by us)
+	DependsOn []string    `yaml:"depends_on"`     // The services that this service depends on
+	Build     BuildConfig `yaml:"build"`          // How the image of the service gets built, if it does
 
 	// Everything within the file, unparsed
 	FullContents ServiceFields
@@ -23,3 +29,75 @@ type ServiceDefinition map[string]ServiceFields
 
 // Alias type for a map of unknown
 type ServiceFields map[string]interface{}
+
+// All the environment files that the service should be
+// started with, in the order they should be applied.
+//
+// The ones from the store the service belongs to come first,
+// followed by the ones the service defines itself in its
+// `env_file` field. Those are relative to the carbon.yml they
+// were written in, not to wherever the compose file ends up, so
+// they get resolved from there.
+func (s *CarbonService) EnvFiles() []string {
+	files := []string{}
+
+	if s.Store != nil {
+		files = append(files, s.Store.Envs...)
+	}
+
+	var own []string
+
+	switch value := s.FullContents["env_file"].(type) {
+	case string:
+		own = append(own, value)
+	case []string:
+		own = append(own, value...)
+	case []interface{}:
+		for _, item := range value {
+			if str, ok := item.(string); ok {
+				own = append(own, str)
+			}
+		}
+	}
+
+	for _, file := range own {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(filepath.Dir(s.Path), file)
+		}
+
+		files = append(files, file)
+	}
+
+	return files
+}
+
+// Whether the image of the service gets built
+// from a Dockerfile instead of being pulled.
+func (s *CarbonService) Builds() bool {
+	return s.Build.Context != ""
+}
+
+// The directory the image of the service gets built from.
+//
+// Like environment files, it's relative to the carbon.yml it
+// was written in, so it gets resolved from there.
+func (s *CarbonService) BuildContext() string {
+	if s.Build.Context == "" || filepath.IsAbs(s.Build.Context) {
+		return s.Build.Context
+	}
+
+	return filepath.Join(filepath.Dir(s.Path), s.Build.Context)
+}
+
+// The image the service runs.
+//
+// Services that only define how to build their image don't have
+// a name for it, so they get one generated from the service name,
+// which is what the built image gets tagged with.
+func (s *CarbonService) ImageTag() string {
+	if s.Image != "" || !s.Builds() {
+		return s.Image
+	}
+
+	return "co2/" + strings.ToLower(s.Name) + ":latest"
+}
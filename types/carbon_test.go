@@ -0,0 +1,93 @@
+package types
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestEnvFilesPutsStoreFilesFirst(t *testing.T) {
+	service := CarbonService{
+		Path: "/code/a/carbon.yml",
+		Store: &Store{
+			Envs: []string{"/code/.env", "/code/.env.local"},
+		},
+		FullContents: ServiceFields{
+			"env_file": "service.env",
+		},
+	}
+
+	files := service.EnvFiles()
+	expected := []string{"/code/.env", "/code/.env.local", filepath.Join("/code/a", "service.env")}
+
+	if len(files) != len(expected) {
+		t.Fatalf("Expected %d files, got %d", len(expected), len(files))
+	}
+
+	for i := range expected {
+		if files[i] != expected[i] {
+			t.Errorf("Expected %s at %d, got %s", expected[i], i, files[i])
+		}
+	}
+}
+
+func TestEnvFilesResolvesListsNextToTheCarbonFile(t *testing.T) {
+	service := CarbonService{
+		Path: "/code/a/carbon.yml",
+		FullContents: ServiceFields{
+			"env_file": []interface{}{"one.env", "/absolute/two.env"},
+		},
+	}
+
+	files := service.EnvFiles()
+
+	if files[0] != filepath.Join("/code/a", "one.env") {
+		t.Error("Expected relative env files to be resolved next to the carbon file, got", files[0])
+	}
+
+	if files[1] != "/absolute/two.env" {
+		t.Error("Expected absolute env files to stay the same, got", files[1])
+	}
+}
+
+func TestEnvFilesIsEmptyWithoutAnyFiles(t *testing.T) {
+	service := CarbonService{
+		FullContents: ServiceFields{},
+	}
+
+	if len(service.EnvFiles()) != 0 {
+		t.Error("Expected no env files, got", service.EnvFiles())
+	}
+}
+
+func TestBuildContextIsRelativeToTheCarbonFile(t *testing.T) {
+	service := CarbonService{
+		Path:  "/code/a/carbon.yml",
+		Build: BuildConfig{Context: "./app"},
+	}
+
+	if context := service.BuildContext(); context != filepath.Join("/code/a", "app") {
+		t.Error("Expected the context to be next to the carbon file, got", context)
+	}
+
+	service.Build.Context = "/absolute"
+	if context := service.BuildContext(); context != "/absolute" {
+		t.Error("Expected absolute contexts to stay as they are, got", context)
+	}
+}
+
+func TestImageTagIsGeneratedForBuildOnlyServices(t *testing.T) {
+	built := CarbonService{Name: "Api", Build: BuildConfig{Context: "."}}
+	if tag := built.ImageTag(); tag != "co2/api:latest" {
+		t.Error("Expected a generated tag, got", tag)
+	}
+
+	named := CarbonService{Name: "api", Image: "org/api:dev", Build: BuildConfig{Context: "."}}
+	if tag := named.ImageTag(); tag != "org/api:dev" {
+		t.Error("Expected the image of the service to be used, got", tag)
+	}
+
+	pulled := CarbonService{Name: "db", Image: "postgres"}
+	if tag := pulled.ImageTag(); tag != "postgres" || pulled.Builds() {
+		t.Error("Expected services without a build section not to build, got", tag)
+	}
+}
@@ -3,7 +3,17 @@ package types
 // Wrapper around our specific way of handling commands.
 // Each command needs to have a label so that the output
 // is formatted nicely.
+//
+// The arguments are what actually gets run, the text is
+// the same command written out for people to read.
+//
+// If there's a filter, only the lines of output it lets
+// through ever get printed. The environment is added on top
+// of the one carbon itself runs with, as `KEY=value`.
 type Command struct {
-	Text  string
-	Label string
+	Text   string
+	Args   []string
+	Env    []string
+	Label  string
+	Filter func(line string) bool
 }
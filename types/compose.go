@@ -21,10 +21,16 @@ import (
 // in the docker compose spec so they will be ignored
 // when marshalling the file.
 type ComposeFile struct {
-	Name          string            `yaml:"-"`        // The name of the compose file without the unique id
-	Version       string            `yaml:"version"`  // The version of the compose file
-	Services      ServiceDefinition `yaml:"services"` // A map of all services this compose file contains
-	GeneratedName string            `yaml:"-"`        // The name of the compose file with the unique id prepended
+	Name          string                    `yaml:"-"`                  // The name of the compose file without the unique id
+	Version       string                    `yaml:"version"`            // The version of the compose file
+	Services      ServiceDefinition         `yaml:"services"`           // A map of all services this compose file contains
+	Networks      map[string]ComposeNetwork `yaml:"networks,omitempty"` // The networks the services join, besides the default one
+	GeneratedName string                    `yaml:"-"`                  // The name of the compose file with the unique id prepended
+}
+
+// A network definition within a compose file.
+type ComposeNetwork struct {
+	External bool `yaml:"external,omitempty"` // Whether the network exists outside of the compose file
 }
 
 func NewComposeFile() ComposeFile {
@@ -32,6 +38,7 @@ func NewComposeFile() ComposeFile {
 		Name:     "carbon.docker-compose.yml",
 		Version:  "3",
 		Services: make(ServiceDefinition),
+		Networks: make(map[string]ComposeNetwork),
 	}
 }
 
@@ -8,18 +8,69 @@ import (
 // Container model for our own database
 // specification of a container.
 type Container struct {
-	Id          int64     // Primary key from the database
-	DockerUid   string    // The ID that docker gives to each container
-	Uid         string    // A unique hash for the container based on the image and name
-	Name        string    // The unique container name that we generate when starting the container
-	Image       string    // The image of the container
-	ServiceName string    // The name of the service in the compose file
-	ComposeFile string    // The compose file this container belongs to
-	Ports       string    // All exposed ports in a comma separated list
-	Status      string    // The current status of the container (This isn't alive within the local database, just the docker api)
-	CreatedAt   time.Time // Creation time of the container
+	Id          int64             // Primary key from the database
+	DockerUid   string            // The ID that docker gives to each container
+	Uid         string            // A unique hash for the container based on the image and name
+	Name        string            // The unique container name that we generate when starting the container
+	Image       string            // The image of the container
+	ServiceName string            // The name of the service in the compose file
+	ComposeFile string            // The compose file this container belongs to
+	CarbonFile  string            // The carbon.yml file the service was defined in
+	StartArgs   []string          // The arguments the container was started with
+	Ports       Ports             // All the ports of the container, published or not
+	Status      string            // The current status of the container (This isn't alive within the local database, just the docker api)
+	StoreId     int64             // The database key of the store the container was started from, if any
+	StoreUid    string            // The unique identifier of the store the container was started from, if any
+	Host        string            // The docker host the container runs on, empty for the default one
+	Labels      map[string]string // The labels docker knows the container by (This isn't stored in the local database)
+	State       ContainerState    // What docker knows about the state of the container (This isn't stored in the local database either)
+	CreatedAt   time.Time         // Creation time of the container
 }
 
 func (c *Container) Hash() {
 	c.Uid = helpers.Hash(c.Image+c.Name, 4)
 }
+
+// The state docker reports for a single container
+// when it gets inspected.
+type ContainerState struct {
+	Status     string    // One of created, running, paused, restarting, removing, exited or dead
+	Running    bool      // Whether the container is currently running
+	Health     string    // The health check status, empty if the container has no health check
+	ExitCode   int       // The exit code of the last run
+	OOMKilled  bool      // Whether the last run was killed for running out of memory
+	StartedAt  time.Time // When the container was last started
+	FinishedAt time.Time // When the container last exited
+}
+
+// Something that happened to a container, as reported
+// by the docker daemon.
+type ContainerEvent struct {
+	Action    string            // What happened, like start, die, oom or health_status
+	Health    string            // The new health status, only set for health_status events
+	ExitCode  string            // The exit code of the container, only set for die events
+	DockerUid string            // The ID docker gave the container
+	Name      string            // The name of the container
+	Labels    map[string]string // All the labels of the container
+	Time      time.Time         // When it happened
+}
+
+// A sample of the resources a container is using.
+type ContainerStats struct {
+	CPUPercent  float64 // How much of the available cpu time the container used, across all cores
+	MemoryUsage uint64  // Memory used in bytes, without the page cache
+	MemoryLimit uint64  // How much memory the container is allowed to use
+	NetworkRx   uint64  // Bytes received over all networks
+	NetworkTx   uint64  // Bytes sent over all networks
+	BlockRead   uint64  // Bytes read from block devices
+	BlockWrite  uint64  // Bytes written to block devices
+}
+
+// How much of its limit the container is using, as a percentage.
+func (s ContainerStats) MemoryPercent() float64 {
+	if s.MemoryLimit == 0 {
+		return 0
+	}
+
+	return float64(s.MemoryUsage) / float64(s.MemoryLimit) * 100
+}
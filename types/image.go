@@ -0,0 +1,56 @@
+package types
+
+import (
+	"strings"
+	"time"
+)
+
+// An image that's present locally, as docker knows it.
+type Image struct {
+	Id        string    // The ID docker gives to the image
+	Tags      []string  // Every name:tag the image is known by
+	Digests   []string  // Every name@digest the image is known by
+	Size      int64     // The size of the image on disk, in bytes
+	CreatedAt time.Time // When the image was built
+}
+
+// Returns the reference of an image the same way docker lists
+// it locally, so that references written in different ways
+// can be compared with each other.
+//
+// The default registry is left out, like docker does, and
+// references without a tag or digest get the `latest` tag.
+func ImageReference(image string) string {
+	for _, prefix := range []string{"docker.io/library/", "index.docker.io/library/", "docker.io/", "index.docker.io/"} {
+		if strings.HasPrefix(image, prefix) {
+			image = strings.TrimPrefix(image, prefix)
+			break
+		}
+	}
+
+	if strings.Contains(image, "@") {
+		return image
+	}
+
+	// A colon before the last slash belongs to a registry port
+	name := image[strings.LastIndex(image, "/")+1:]
+	if !strings.Contains(name, ":") {
+		image += ":latest"
+	}
+
+	return image
+}
+
+// Whether the image is known by the given reference,
+// no matter how the reference is written.
+func (i Image) Is(reference string) bool {
+	reference = ImageReference(reference)
+
+	for _, known := range append(i.Tags, i.Digests...) {
+		if ImageReference(known) == reference {
+			return true
+		}
+	}
+
+	return false
+}
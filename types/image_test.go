@@ -0,0 +1,38 @@
+package types
+
+import "testing"
+
+func TestImageReferenceMatchesTheLocalNames(t *testing.T) {
+	cases := map[string]string{
+		"nginx":                         "nginx:latest",
+		"nginx:1.21":                    "nginx:1.21",
+		"docker.io/library/nginx":       "nginx:latest",
+		"docker.io/bitnami/redis:6":     "bitnami/redis:6",
+		"localhost:5000/api":            "localhost:5000/api:latest",
+		"ghcr.io/org/app:v2":            "ghcr.io/org/app:v2",
+		"nginx@sha256:0123456789abcdef": "nginx@sha256:0123456789abcdef",
+	}
+
+	for image, expected := range cases {
+		if reference := ImageReference(image); reference != expected {
+			t.Errorf("Expected %s to become %s, got %s", image, expected, reference)
+		}
+	}
+}
+
+func TestImageIsKnownByAnyOfItsNames(t *testing.T) {
+	image := Image{
+		Tags:    []string{"nginx:latest"},
+		Digests: []string{"nginx@sha256:0123"},
+	}
+
+	for _, reference := range []string{"nginx", "docker.io/library/nginx:latest", "nginx@sha256:0123"} {
+		if !image.Is(reference) {
+			t.Error("Expected the image to be known as", reference)
+		}
+	}
+
+	if image.Is("nginx:1.21") {
+		t.Error("Expected the image not to be known as nginx:1.21")
+	}
+}
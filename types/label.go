@@ -0,0 +1,69 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Labels that carbon puts on every container it starts so
+// that the containers can always be traced back to where they
+// came from, even without the database.
+const (
+	LabelService     = "co2.service"      // The name of the carbon service
+	LabelStore       = "co2.store"        // The unique identifier of the store the service came from
+	LabelCarbonFile  = "co2.carbon-file"  // The carbon.yml file the service was defined in
+	LabelComposeFile = "co2.compose-file" // The generated compose file the container was started from
+	LabelStartedAt   = "co2.started-at"   // When carbon started the container, in RFC3339
+	LabelStartArgs   = "co2.start-args"   // All the services that were started together with this one
+	LabelNetwork     = "co2.network"      // Put on the network carbon creates for all of its services to share
+)
+
+// Merges the given labels into the labels a compose service
+// already defines, with the given ones taking priority.
+//
+// Compose allows labels to be defined either as a map or as a
+// list of `key=value` strings so both of them are understood. The
+// result is always a map since that's the easiest to work with.
+func MergeLabels(existing interface{}, labels map[string]string) map[string]string {
+	merged := map[string]string{}
+
+	switch existing := existing.(type) {
+	case map[interface{}]interface{}:
+		for key, value := range existing {
+			merged[fmt.Sprint(key)] = label(value)
+		}
+	case map[string]interface{}:
+		for key, value := range existing {
+			merged[key] = label(value)
+		}
+	case map[string]string:
+		for key, value := range existing {
+			merged[key] = value
+		}
+	case []interface{}:
+		for _, entry := range existing {
+			parts := strings.SplitN(fmt.Sprint(entry), "=", 2)
+			if len(parts) == 1 {
+				parts = append(parts, "")
+			}
+
+			merged[parts[0]] = parts[1]
+		}
+	}
+
+	for key, value := range labels {
+		merged[key] = value
+	}
+
+	return merged
+}
+
+// Compose allows labels without a value, which come
+// through as nil, and those should be empty, not "<nil>".
+func label(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+
+	return fmt.Sprint(value)
+}
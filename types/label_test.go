@@ -0,0 +1,49 @@
+package types
+
+import "testing"
+
+func TestMergeLabelsUnderstandsLists(t *testing.T) {
+	merged := MergeLabels([]interface{}{"team=core", "empty"}, map[string]string{LabelService: "api"})
+
+	expected := map[string]string{"team": "core", "empty": "", LabelService: "api"}
+
+	if len(merged) != len(expected) {
+		t.Fatalf("Expected %d labels, got %v", len(expected), merged)
+	}
+
+	for key, value := range expected {
+		if merged[key] != value {
+			t.Errorf("Expected %s to be %q, got %q", key, value, merged[key])
+		}
+	}
+}
+
+func TestMergeLabelsUnderstandsMaps(t *testing.T) {
+	merged := MergeLabels(
+		map[interface{}]interface{}{"team": "core", "replicas": 2, "empty": nil},
+		map[string]string{},
+	)
+
+	if merged["team"] != "core" || merged["replicas"] != "2" || merged["empty"] != "" {
+		t.Error("Expected all the existing labels to be kept, got", merged)
+	}
+}
+
+func TestMergeLabelsOverridesExistingOnes(t *testing.T) {
+	merged := MergeLabels(
+		map[interface{}]interface{}{LabelService: "spoofed"},
+		map[string]string{LabelService: "api"},
+	)
+
+	if merged[LabelService] != "api" {
+		t.Error("Expected carbon labels to take priority, got", merged[LabelService])
+	}
+}
+
+func TestMergeLabelsWithoutExistingOnes(t *testing.T) {
+	merged := MergeLabels(nil, map[string]string{LabelStore: "abcd"})
+
+	if len(merged) != 1 || merged[LabelStore] != "abcd" {
+		t.Error("Expected only the given labels, got", merged)
+	}
+}
@@ -0,0 +1,9 @@
+package types
+
+import "time"
+
+// A single line of output of a container.
+type LogLine struct {
+	Time time.Time // When docker received the line
+	Text string    // The line itself, without a trailing newline
+}
@@ -0,0 +1,51 @@
+package types
+
+import "fmt"
+
+// A network a single container is attached to,
+// as docker sees it from the side of the container.
+type ContainerNetwork struct {
+	Name    string   // The name of the network
+	Id      string   // The ID docker gives to the network
+	IP      string   // The address of the container within the network
+	Aliases []string // The names the container can be reached by within the network
+}
+
+// Adds the given network, along with the aliases the service
+// should be known by in it, to the networks a compose service
+// already joins.
+//
+// Compose only joins a service to the default network of its file
+// as long as it doesn't list any networks itself, so the default
+// one gets listed as well when there weren't any. Both the list
+// and the map way of writing networks are understood.
+func JoinNetwork(existing interface{}, network string, aliases ...string) map[string]interface{} {
+	joined := map[string]interface{}{}
+
+	switch existing := existing.(type) {
+	case nil:
+		joined["default"] = nil
+	case []interface{}:
+		for _, name := range existing {
+			joined[fmt.Sprint(name)] = nil
+		}
+	case []string:
+		for _, name := range existing {
+			joined[name] = nil
+		}
+	case map[interface{}]interface{}:
+		for name, settings := range existing {
+			joined[fmt.Sprint(name)] = settings
+		}
+	case map[string]interface{}:
+		for name, settings := range existing {
+			joined[name] = settings
+		}
+	}
+
+	joined[network] = map[string]interface{}{
+		"aliases": aliases,
+	}
+
+	return joined
+}
@@ -0,0 +1,30 @@
+package types
+
+import "testing"
+
+func TestJoinNetworkKeepsTheDefaultNetwork(t *testing.T) {
+	joined := JoinNetwork(nil, "carbon", "api")
+
+	if _, ok := joined["default"]; !ok || len(joined) != 2 {
+		t.Fatal("Expected the default network to be kept, got", joined)
+	}
+
+	aliases := joined["carbon"].(map[string]interface{})["aliases"].([]string)
+	if len(aliases) != 1 || aliases[0] != "api" {
+		t.Error("Expected the service to be aliased in the network, got", aliases)
+	}
+}
+
+func TestJoinNetworkUnderstandsListsAndMaps(t *testing.T) {
+	listed := JoinNetwork([]interface{}{"backend"}, "carbon", "api")
+	if _, ok := listed["backend"]; !ok || len(listed) != 2 {
+		t.Error("Expected the listed networks to be kept, got", listed)
+	}
+
+	settings := map[interface{}]interface{}{"ipv4_address": "10.0.0.2"}
+	mapped := JoinNetwork(map[interface{}]interface{}{"backend": settings}, "carbon", "api")
+
+	if len(mapped) != 2 || mapped["backend"].(map[interface{}]interface{})["ipv4_address"] != "10.0.0.2" {
+		t.Error("Expected the mapped networks to keep their settings, got", mapped)
+	}
+}
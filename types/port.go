@@ -0,0 +1,293 @@
+package types
+
+import (
+	"co2/helpers"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Single port of a container, and where it's
+// published on the host if it's published at all.
+type Port struct {
+	HostIp        string `json:"host_ip,omitempty"`   // The host IP the port is published on
+	HostPort      int    `json:"host_port,omitempty"` // The port on the host, 0 if it's not published
+	ContainerPort int    `json:"container_port"`      // The port within the container
+	Protocol      string `json:"protocol"`            // tcp, udp or sctp
+}
+
+// Alias type for all the ports of a container
+type Ports []Port
+
+// Formats the port the same way docker does, with where it's
+// published on the host pointing to the port in the container.
+//
+// Ports that aren't published only have the container side.
+func (p Port) String() string {
+	if p.HostPort == 0 {
+		return fmt.Sprintf("%d/%s", p.ContainerPort, p.Protocol)
+	}
+
+	ip := p.HostIp
+	if anyIp(ip) {
+		ip = "0.0.0.0"
+	}
+
+	if strings.Contains(ip, ":") {
+		ip = "[" + ip + "]"
+	}
+
+	return fmt.Sprintf("%s:%d->%d/%s", ip, p.HostPort, p.ContainerPort, p.Protocol)
+}
+
+// A link to the port that can be opened in a browser, or
+// nothing if the port isn't published over tcp.
+//
+// Ports published on every IP are reachable through localhost,
+// which is shorter and easier to remember than anything else.
+func (p Port) Link() string {
+	if p.HostPort == 0 || p.Protocol != "tcp" {
+		return ""
+	}
+
+	host := "localhost"
+	if !anyIp(p.HostIp) && p.HostIp != "127.0.0.1" && p.HostIp != "::1" {
+		host = p.HostIp
+
+		if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+	}
+
+	return fmt.Sprintf("http://%s:%d", host, p.HostPort)
+}
+
+// Removes all the duplicates from the ports and sorts them
+// by the port within the container.
+//
+// Docker reports ports that are published on every IP once for
+// IPv4 and once for IPv6, those are the same port as far as anyone
+// cares so they're collapsed into one.
+func (p Ports) Collapse() Ports {
+	collapsed := Ports{}
+	seen := map[Port]bool{}
+
+	for _, port := range p {
+		if anyIp(port.HostIp) {
+			port.HostIp = ""
+		}
+
+		if port.HostPort == 0 {
+			port.HostIp = ""
+		}
+
+		if seen[port] {
+			continue
+		}
+
+		seen[port] = true
+		collapsed = append(collapsed, port)
+	}
+
+	sort.Slice(collapsed, func(i, j int) bool {
+		a, b := collapsed[i], collapsed[j]
+
+		if a.ContainerPort != b.ContainerPort {
+			return a.ContainerPort < b.ContainerPort
+		}
+
+		if a.Protocol != b.Protocol {
+			return a.Protocol < b.Protocol
+		}
+
+		if a.HostPort != b.HostPort {
+			return a.HostPort < b.HostPort
+		}
+
+		return a.HostIp < b.HostIp
+	})
+
+	return collapsed
+}
+
+// Formats all the ports into a comma separated list,
+// without any duplicates.
+func (p Ports) String() string {
+	formatted := []string{}
+
+	for _, port := range p.Collapse() {
+		formatted = append(formatted, port.String())
+	}
+
+	return strings.Join(formatted, ", ")
+}
+
+// All the links to the ports that can be opened in a browser.
+func (p Ports) Links() []string {
+	links := []string{}
+
+	for _, port := range p.Collapse() {
+		if link := port.Link(); link != "" && !helpers.Contains(links, link) {
+			links = append(links, link)
+		}
+	}
+
+	return links
+}
+
+// Checks whether or not two ports would fight over the same
+// port on the host if they were both published at once.
+//
+// Ports that aren't published never conflict with anything,
+// and an empty host IP means every IP so it conflicts with all
+// of them.
+func (p Port) Conflicts(other Port) bool {
+	if p.HostPort == 0 || p.HostPort != other.HostPort || p.Protocol != other.Protocol {
+		return false
+	}
+
+	return anyIp(p.HostIp) || anyIp(other.HostIp) || p.HostIp == other.HostIp
+}
+
+// Whether or not the given host IP means all of them.
+func anyIp(ip string) bool {
+	return ip == "" || ip == "0.0.0.0" || ip == "::"
+}
+
+// Parses all the ports within a compose `ports` definition,
+// both the short `[ip:]host:container[/protocol]` syntax and
+// the long syntax with all the fields spelled out.
+//
+// Port ranges are expanded into one port each. Anything that
+// can't be understood is ignored, docker will complain about it
+// later anyway.
+func ParsePorts(definition interface{}) Ports {
+	ports := Ports{}
+
+	items, ok := definition.([]interface{})
+	if !ok {
+		return ports
+	}
+
+	for _, item := range items {
+		switch value := item.(type) {
+		case string:
+			ports = append(ports, parseShortPort(value)...)
+		case int:
+			ports = append(ports, Port{ContainerPort: value, Protocol: "tcp"})
+		case map[interface{}]interface{}:
+			ports = append(ports, parseLongPort(value))
+		}
+	}
+
+	return ports
+}
+
+// Parses a single port in the short syntax.
+func parseShortPort(spec string) Ports {
+	protocol := "tcp"
+	if parts := strings.SplitN(spec, "/", 2); len(parts) == 2 {
+		spec, protocol = parts[0], parts[1]
+	}
+
+	ip, host, container := "", "", spec
+
+	// IPv6 addresses are wrapped in brackets
+	if strings.HasPrefix(spec, "[") {
+		if end := strings.Index(spec, "]"); end != -1 {
+			ip = spec[1:end]
+			spec = strings.TrimPrefix(spec[end+1:], ":")
+			container = spec
+		}
+	}
+
+	parts := strings.Split(spec, ":")
+	switch len(parts) {
+	case 2:
+		host, container = parts[0], parts[1]
+	case 3:
+		ip, host, container = parts[0], parts[1], parts[2]
+	}
+
+	hosts := portRange(host)
+	containers := portRange(container)
+	ports := Ports{}
+
+	for i, containerPort := range containers {
+		port := Port{
+			HostIp:        ip,
+			ContainerPort: containerPort,
+			Protocol:      protocol,
+		}
+
+		if i < len(hosts) {
+			port.HostPort = hosts[i]
+		}
+
+		ports = append(ports, port)
+	}
+
+	return ports
+}
+
+// Parses a single port in the long syntax.
+func parseLongPort(spec map[interface{}]interface{}) Port {
+	port := Port{Protocol: "tcp"}
+
+	if value, ok := spec["host_ip"].(string); ok {
+		port.HostIp = value
+	}
+
+	if value, ok := spec["protocol"].(string); ok {
+		port.Protocol = value
+	}
+
+	port.ContainerPort = portNumber(spec["target"])
+	port.HostPort = portNumber(spec["published"])
+
+	return port
+}
+
+// Expands a port or a range of ports (`8000-8010`)
+// into all the port numbers it contains.
+func portRange(spec string) []int {
+	if spec == "" {
+		return []int{}
+	}
+
+	bounds := strings.SplitN(spec, "-", 2)
+	start, err := strconv.Atoi(bounds[0])
+	if err != nil {
+		return []int{}
+	}
+
+	end := start
+	if len(bounds) == 2 {
+		end, err = strconv.Atoi(bounds[1])
+		if err != nil || end < start {
+			return []int{}
+		}
+	}
+
+	ports := []int{}
+	for port := start; port <= end; port++ {
+		ports = append(ports, port)
+	}
+
+	return ports
+}
+
+// Reads a port number that could be written
+// as either a number or a string.
+func portNumber(value interface{}) int {
+	switch number := value.(type) {
+	case int:
+		return number
+	case string:
+		parsed, _ := strconv.Atoi(number)
+		return parsed
+	}
+
+	return 0
+}
@@ -0,0 +1,122 @@
+package types
+
+import "testing"
+
+func TestParsePortsUnderstandsTheShortSyntax(t *testing.T) {
+	ports := ParsePorts([]interface{}{
+		"80",
+		"8080:80",
+		"127.0.0.1:5432:5432/udp",
+		"9000-9001:9000-9001",
+	})
+
+	expected := Ports{
+		{ContainerPort: 80, Protocol: "tcp"},
+		{HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+		{HostIp: "127.0.0.1", HostPort: 5432, ContainerPort: 5432, Protocol: "udp"},
+		{HostPort: 9000, ContainerPort: 9000, Protocol: "tcp"},
+		{HostPort: 9001, ContainerPort: 9001, Protocol: "tcp"},
+	}
+
+	if len(ports) != len(expected) {
+		t.Fatalf("Expected %d ports, got %d", len(expected), len(ports))
+	}
+
+	for i := range expected {
+		if ports[i] != expected[i] {
+			t.Errorf("Expected %+v at %d, got %+v", expected[i], i, ports[i])
+		}
+	}
+}
+
+func TestParsePortsUnderstandsTheLongSyntax(t *testing.T) {
+	ports := ParsePorts([]interface{}{
+		map[interface{}]interface{}{
+			"target":    80,
+			"published": "8080",
+			"host_ip":   "127.0.0.1",
+		},
+	})
+
+	expected := Port{HostIp: "127.0.0.1", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"}
+
+	if len(ports) != 1 || ports[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, ports)
+	}
+}
+
+func TestParsePortsIgnoresAnythingElse(t *testing.T) {
+	if len(ParsePorts("8080:80")) != 0 {
+		t.Error("Expected no ports when the definition isn't a list")
+	}
+}
+
+func TestPortsConflictOnlyWhenPublishedOnTheSameHostPort(t *testing.T) {
+	a := Port{HostPort: 8080, ContainerPort: 80, Protocol: "tcp"}
+	b := Port{HostIp: "127.0.0.1", HostPort: 8080, ContainerPort: 3000, Protocol: "tcp"}
+	c := Port{HostIp: "127.0.0.2", HostPort: 8080, ContainerPort: 3000, Protocol: "tcp"}
+	d := Port{HostPort: 8080, ContainerPort: 80, Protocol: "udp"}
+	e := Port{ContainerPort: 80, Protocol: "tcp"}
+
+	if !a.Conflicts(b) {
+		t.Error("Expected a port on every IP to conflict with a specific IP")
+	}
+
+	if b.Conflicts(c) {
+		t.Error("Expected ports on different IPs not to conflict")
+	}
+
+	if a.Conflicts(d) {
+		t.Error("Expected ports with different protocols not to conflict")
+	}
+
+	if e.Conflicts(e) {
+		t.Error("Expected unpublished ports never to conflict")
+	}
+}
+
+func TestPortsStringCollapsesDuplicates(t *testing.T) {
+	ports := Ports{
+		{HostIp: "::", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+		{HostIp: "0.0.0.0", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+		{HostIp: "127.0.0.1", HostPort: 5432, ContainerPort: 5432, Protocol: "tcp"},
+		{ContainerPort: 443, Protocol: "tcp"},
+	}
+
+	expected := "0.0.0.0:8080->80/tcp, 443/tcp, 127.0.0.1:5432->5432/tcp"
+
+	if ports.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, ports.String())
+	}
+}
+
+func TestPortStringWrapsIPv6Addresses(t *testing.T) {
+	port := Port{HostIp: "::1", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"}
+
+	if port.String() != "[::1]:8080->80/tcp" {
+		t.Error("Expected the IPv6 address to be wrapped, got", port.String())
+	}
+}
+
+func TestPortsLinksOnlyIncludePublishedTcpPorts(t *testing.T) {
+	ports := Ports{
+		{HostIp: "::", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+		{HostIp: "0.0.0.0", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+		{HostIp: "192.168.1.10", HostPort: 9000, ContainerPort: 9000, Protocol: "tcp"},
+		{HostPort: 53, ContainerPort: 53, Protocol: "udp"},
+		{ContainerPort: 443, Protocol: "tcp"},
+	}
+
+	links := ports.Links()
+	expected := []string{"http://localhost:8080", "http://192.168.1.10:9000"}
+
+	if len(links) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, links)
+	}
+
+	for i := range expected {
+		if links[i] != expected[i] {
+			t.Errorf("Expected %s at %d, got %s", expected[i], i, links[i])
+		}
+	}
+}
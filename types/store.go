@@ -5,9 +5,13 @@ import "time"
 // Store model for our own database
 // specification of a store.
 type Store struct {
-	Id        int64     // Database key
-	Uid       string    // Unique identifier for the store
-	Path      string    // The path to the store
-	Env       string    // The environment file linked to this store
-	CreatedAt time.Time // The time the store was created at
+	Id          int64             // Database key
+	Uid         string            // Unique identifier for the store
+	Path        string            // The path to the store
+	Envs        []string          // The environment files linked to this store, in order
+	Description string            // What the store is about
+	Labels      map[string]string // Free-form labels for the store
+	Depth       int               // How many directories deep to look for carbon.yml files
+	Host        string            // The docker host the services of the store run on, empty for the default one
+	CreatedAt   time.Time         // The time the store was created at
 }
@@ -0,0 +1,12 @@
+package types
+
+// A volume mounted into a single container.
+type Volume struct {
+	Name        string // The name of the volume, a long hash for anonymous ones
+	Destination string // Where the volume is mounted within the container
+	Anonymous   bool   // Whether the volume was created without a name
+	Size        int64  // The size of the volume on disk in bytes, -1 if docker doesn't know
+	Container   string // The name of the container the volume is mounted into
+	Service     string // The carbon service the container belongs to, if any
+	Running     bool   // Whether the container is currently running
+}
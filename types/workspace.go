@@ -0,0 +1,83 @@
+package types
+
+import (
+	"io/ioutil"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Workspace file definition
+// A workspace is a portable list of stores that can be
+// committed somewhere and shared with others, so everyone
+// ends up with the same stores without registering them by hand.
+//
+// The paths within a workspace are always relative to a root
+// directory which is provided when exporting or importing, since
+// everyone keeps their code somewhere different.
+type Workspace struct {
+	Stores []WorkspaceStore `yaml:"stores"` // All the stores in the workspace
+}
+
+// Single store definition within a workspace file.
+type WorkspaceStore struct {
+	Uid         string            `yaml:"uid"`                   // Unique identifier for the store
+	Path        string            `yaml:"path"`                  // The path to the store, relative to the root
+	Envs        []string          `yaml:"envs,omitempty"`        // The environment files, relative to the root
+	Description string            `yaml:"description,omitempty"` // What the store is about
+	Labels      map[string]string `yaml:"labels,omitempty"`      // Free-form labels for the store
+	Depth       int               `yaml:"depth"`                 // How deep to look for carbon.yml files
+	Host        string            `yaml:"host,omitempty"`        // The docker host the services of the store run on
+}
+
+// Workspaces used to only have a single `env` file per store,
+// and the ones exported back then still have to import the same.
+// The old file comes before the new ones if there's both.
+func (s *WorkspaceStore) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain WorkspaceStore
+
+	var store struct {
+		Store plain  `yaml:",inline"`
+		Env   string `yaml:"env"`
+	}
+
+	if err := unmarshal(&store); err != nil {
+		return err
+	}
+
+	*s = WorkspaceStore(store.Store)
+
+	if store.Env != "" {
+		s.Envs = append([]string{store.Env}, s.Envs...)
+	}
+
+	return nil
+}
+
+// Reads and parses the workspace file at the given path.
+func LoadWorkspace(path string) (Workspace, error) {
+	var workspace Workspace
+
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return workspace, err
+	}
+
+	err = yaml.Unmarshal(contents, &workspace)
+
+	return workspace, err
+}
+
+// Convert the workspace into yaml and save it to
+// the given path.
+//
+// Unlike the compose files, this goes wherever the user
+// wants it to go, so any errors are returned instead of
+// panicking.
+func (w *Workspace) Save(path string) error {
+	contents, err := yaml.Marshal(w)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, contents, 0644)
+}
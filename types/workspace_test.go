@@ -0,0 +1,50 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestWorkspaceStoreReadsTheEnvFiles(t *testing.T) {
+	var workspace Workspace
+
+	err := yaml.Unmarshal([]byte(`
+stores:
+  - uid: uid1
+    path: a
+    envs: [a/.env, a/.env.local]
+    depth: 3
+`), &workspace)
+	if err != nil {
+		t.Fatal("Expected no error, got", err)
+	}
+
+	store := workspace.Stores[0]
+
+	if store.Uid != "uid1" || store.Depth != 3 || strings.Join(store.Envs, ",") != "a/.env,a/.env.local" {
+		t.Error("Expected the whole store to be read, got", store)
+	}
+}
+
+func TestWorkspaceStoreStillReadsTheOldEnvKey(t *testing.T) {
+	var workspace Workspace
+
+	err := yaml.Unmarshal([]byte(`
+stores:
+  - uid: uid1
+    path: a
+    env: a/.env
+    depth: 3
+`), &workspace)
+	if err != nil {
+		t.Fatal("Expected no error, got", err)
+	}
+
+	store := workspace.Stores[0]
+
+	if store.Path != "a" || strings.Join(store.Envs, ",") != "a/.env" {
+		t.Error("Expected the old env file to be read, got", store)
+	}
+}